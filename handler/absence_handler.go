@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AbsenceHandler struct {
+	Service *service.AbsenceService
+}
+
+func NewAbsenceHandler(service *service.AbsenceService) *AbsenceHandler {
+	return &AbsenceHandler{Service: service}
+}
+
+// GetAbsences: GET /absences?from=2025-12-01&to=2025-12-14&supervisor_nik=SPV001
+// Tanpa from/to, dikembalikan ketidakhadiran hari ini.
+func (h *AbsenceHandler) GetAbsences(c echo.Context) error {
+	filter := model.AbsenceFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	today := sitetime.Today().Format("2006-01-02")
+	if filter.From == "" {
+		filter.From = today
+	}
+	if filter.To == "" {
+		filter.To = filter.From
+	}
+	from, errFrom := sitetime.ParseDate(filter.From)
+	to, errTo := sitetime.ParseDate(filter.To)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'from' dan 'to' harus berformat YYYY-MM-DD",
+		})
+	}
+
+	absences, err := h.Service.Repo.GetAbsences(from, to, filter.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil data ketidakhadiran",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, absences)
+}
+
+// DetectAbsences: POST /absences/detect, menjalankan deteksi saat ini juga
+func (h *AbsenceHandler) DetectAbsences(c echo.Context) error {
+	absences, err := h.Service.Detect(sitetime.Now())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mendeteksi ketidakhadiran",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, echo.Map{
+		"recorded": len(absences),
+		"absences": absences,
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AttendanceHandler struct {
+	Service *service.AttendanceService
+}
+
+func NewAttendanceHandler(service *service.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{Service: service}
+}
+
+// GetDailySummary: POST /summary {"date": "2025-12-14", "department_id": 1}
+func (h *AttendanceHandler) GetDailySummary(c echo.Context) error {
+	request := model.DailySummaryRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	date, err := sitetime.ParseDate(request.Date)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format tanggal salah. Gunakan format: YYYY-MM-DD",
+		})
+	}
+
+	summaries, err := h.Service.DailySummary(date, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Gagal menghitung ringkasan absensi",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, summaries)
+}
+
+// GetMonthlyRecap: POST /recap {"month": "2025-12", "department_id": 1, "line_id": 2}
+func (h *AttendanceHandler) GetMonthlyRecap(c echo.Context) error {
+	request := model.MonthlyRecapRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	month, err := sitetime.ParseMonth(request.Month)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format bulan salah. Gunakan format: YYYY-MM",
+		})
+	}
+
+	recaps, err := h.Service.MonthlyRecap(month, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Gagal menghitung rekap bulanan",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, recaps)
+}
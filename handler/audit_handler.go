@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	Repo *repository.AuditRepository
+}
+
+func NewAuditHandler(repo *repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{Repo: repo}
+}
+
+// auditContext: aktor dari token (RequireActor), alasan dari request, IP dari
+// e.IPExtractor (lihat IPExtractorFromEnv). Field actor di body diabaikan agar
+// entri audit tidak bisa mengatasnamakan orang lain.
+// ok false jika aktor atau alasan kosong.
+func auditContext(c echo.Context, reason string) (model.AuditContext, bool) {
+	audit := model.AuditContext{
+		Actor:    authenticatedActor(c),
+		Reason:   strings.TrimSpace(reason),
+		ClientIP: c.RealIP(),
+	}
+	return audit, audit.Actor != "" && audit.Reason != ""
+}
+
+// GetAuditLog: GET /audit?nik=&from=&to=&actor=&action=&limit=
+func (h *AuditHandler) GetAuditLog(c echo.Context) error {
+	filter := model.AuditFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	for _, value := range []string{filter.From, filter.To} {
+		if value == "" {
+			continue
+		}
+		if _, err := sitetime.ParseDate(value); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Parameter 'from' dan 'to' harus berformat YYYY-MM-DD",
+			})
+		}
+	}
+	if filter.Limit < 1 || filter.Limit > 1000 {
+		filter.Limit = 200
+	}
+
+	entries, err := h.Repo.GetEntries(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil audit log",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, entries)
+}
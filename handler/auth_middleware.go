@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const actorContextKey = "actor"
+
+// ActorTokens: hash SHA-256 token API -> actor. Disimpan sebagai hash agar
+// lookup map tidak membandingkan token rahasia secara langsung.
+type ActorTokens map[[32]byte]string
+
+// ActorTokensFromEnv: ACTOR_TOKENS="token1:HR01,token2:SPV001"
+func ActorTokensFromEnv() ActorTokens {
+	tokens := make(ActorTokens)
+	for _, pair := range strings.Split(os.Getenv("ACTOR_TOKENS"), ",") {
+		token, actor, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		token, actor = strings.TrimSpace(token), strings.TrimSpace(actor)
+		if !ok || token == "" || actor == "" {
+			continue
+		}
+		tokens[sha256.Sum256([]byte(token))] = actor
+	}
+	return tokens
+}
+
+// RequireActor: middleware untuk aksi yang bergantung pada identitas pemanggil
+// (pemulihan log, keputusan koreksi, lembur dan cuti). Actor diambil dari header
+// "Authorization: Bearer <token>", bukan dari body request.
+func RequireActor(tokens ActorTokens) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			actor := tokens[sha256.Sum256([]byte(strings.TrimSpace(token)))]
+			if !ok || actor == "" {
+				return c.JSON(http.StatusUnauthorized, echo.Map{"message": "Token actor tidak valid"})
+			}
+			c.Set(actorContextKey, actor)
+			return next(c)
+		}
+	}
+}
+
+// authenticatedActor: actor yang diset RequireActor, kosong di route tanpa middleware
+func authenticatedActor(c echo.Context) string {
+	actor, _ := c.Get(actorContextKey).(string)
+	return actor
+}
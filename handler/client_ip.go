@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractorFromEnv: sumber IP klien untuk audit log. Tanpa TRUSTED_PROXIES,
+// IP diambil langsung dari koneksi sehingga header X-Forwarded-For/X-Real-IP
+// kiriman klien tidak bisa memalsukan alamat. Jika aplikasi berada di belakang
+// reverse proxy, isi TRUSTED_PROXIES="10.0.0.0/8,192.168.1.10"; hanya hop dari
+// alamat itu yang dipercaya saat membaca X-Forwarded-For.
+func IPExtractorFromEnv() (echo.IPExtractor, error) {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, value := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, ipRange, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES tidak valid: %q", value)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	if len(options) == 3 {
+		return echo.ExtractIPDirect(), nil
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
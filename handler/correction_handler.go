@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CorrectionHandler struct {
+	Service *service.CorrectionService
+}
+
+func NewCorrectionHandler(service *service.CorrectionService) *CorrectionHandler {
+	return &CorrectionHandler{Service: service}
+}
+
+// CreateRequest: POST /corrections
+// {"nik": "123", "action": "add", "timestamp": "2025-12-14 07:58:00", "direction": "IN", "reason": "...", "requested_by": "LL01"}
+// {"nik": "123", "action": "delete", "log_id": 812, "reason": "...", "requested_by": "LL01"}
+func (h *CorrectionHandler) CreateRequest(c echo.Context) error {
+	request := model.CreateCorrectionRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	correction, err := h.Service.Create(&request)
+	if err != nil {
+		return requestError(c, "Gagal menyimpan pengajuan koreksi", err)
+	}
+	return c.JSON(http.StatusCreated, correction)
+}
+
+// GetRequests: GET /corrections?nik=...&status=submitted&assigned_to=HR
+func (h *CorrectionHandler) GetRequests(c echo.Context) error {
+	filter := model.CorrectionRequestFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+
+	requests, err := h.Service.Repo.ListRequests(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil pengajuan koreksi",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, requests)
+}
+
+// GetRequest: GET /corrections/:id, termasuk komentar
+func (h *CorrectionHandler) GetRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID pengajuan tidak valid"})
+	}
+
+	correction, err := h.Service.Get(id)
+	if err != nil {
+		return requestError(c, "Gagal mengambil pengajuan koreksi", err)
+	}
+	return c.JSON(http.StatusOK, correction)
+}
+
+// ApproveRequest: POST /corrections/:id/approve {"note": "..."}, token HR di header Authorization
+func (h *CorrectionHandler) ApproveRequest(c echo.Context) error {
+	return h.decide(c, func(id int, data *model.DecideRequest) (model.CorrectionRequest, error) {
+		return h.Service.Approve(id, data, c.RealIP())
+	})
+}
+
+// RejectRequest: POST /corrections/:id/reject {"note": "..."}, token HR atau atasan di header Authorization
+func (h *CorrectionHandler) RejectRequest(c echo.Context) error {
+	return h.decide(c, h.Service.Reject)
+}
+
+func (h *CorrectionHandler) decide(c echo.Context, decide func(int, *model.DecideRequest) (model.CorrectionRequest, error)) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID pengajuan tidak valid"})
+	}
+	request := model.DecideRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	// Pemutus dari token (RequireActor); decided_by di body diabaikan
+	request.DecidedBy = authenticatedActor(c)
+
+	correction, err := decide(id, &request)
+	if err != nil {
+		return requestError(c, "Gagal memproses pengajuan koreksi", err)
+	}
+	return c.JSON(http.StatusOK, correction)
+}
+
+// AddComment: POST /corrections/:id/comments {"author": "LL01", "body": "..."}
+func (h *CorrectionHandler) AddComment(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID pengajuan tidak valid"})
+	}
+	request := model.AddCommentRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	comment, err := h.Service.Comment(id, &request)
+	if err != nil {
+		return requestError(c, "Gagal menyimpan komentar", err)
+	}
+	return c.JSON(http.StatusCreated, comment)
+}
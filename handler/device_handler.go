@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"Steril-App/model"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DeviceHandler struct {
+	Repo *repository.DeviceRepository
+}
+
+func NewDeviceHandler(repo *repository.DeviceRepository) *DeviceHandler {
+	return &DeviceHandler{Repo: repo}
+}
+
+// SaveDevice: POST /devices {"code": "GATE-1", "name": "Pintu Utama", "debounce_seconds": 60}
+func (h *DeviceHandler) SaveDevice(c echo.Context) error {
+	request := model.SaveDeviceRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if request.Code == "" || request.Name == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Kode dan nama perangkat wajib diisi",
+		})
+	}
+	if request.Direction != "" {
+		request.Direction = service.NormalizeDirection(request.Direction)
+		if request.Direction == "" {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "direction harus IN, OUT atau kosong",
+			})
+		}
+	}
+	if request.DebounceSeconds != nil && *request.DebounceSeconds < 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "debounce_seconds tidak boleh negatif",
+		})
+	}
+
+	device, err := h.Repo.SaveDevice(&request)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menyimpan perangkat",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, device)
+}
+
+func (h *DeviceHandler) GetDevices(c echo.Context) error {
+	devices, err := h.Repo.GetDevices()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil data perangkat",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, devices)
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"Steril-App/internal/export"
+	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+)
+
+const noDepartment = "Tanpa Departemen"
+
+type ExportHandler struct {
+	Service *service.AttendanceService
+	LogRepo *repository.FingerLogRepository
+	Company export.Company
+}
+
+func NewExportHandler(service *service.AttendanceService, logRepo *repository.FingerLogRepository, company export.Company) *ExportHandler {
+	return &ExportHandler{Service: service, LogRepo: logRepo, Company: company}
+}
+
+func bindExportRequest(c echo.Context) (model.ExportRequest, error) {
+	request := model.ExportRequest{}
+	if err := c.Bind(&request); err != nil {
+		return request, err
+	}
+	if request.Format == "" {
+		request.Format = export.FormatXLSX
+	}
+	if !export.IsSupported(request.Format) {
+		return request, fmt.Errorf("format '%s' tidak didukung, gunakan csv, xlsx atau pdf", request.Format)
+	}
+	return request, nil
+}
+
+// writeExport: header dikirim sebelum data mulai mengalir, jadi error di tengah
+// jalan hanya bisa dicatat di log (response sudah terlanjur 200).
+func (h *ExportHandler) writeExport(c echo.Context, format, filename string, report export.Report) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, export.ContentType(format))
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	res.WriteHeader(http.StatusOK)
+
+	if err := export.Write(res, format, report, h.Company); err != nil {
+		log.Printf("Export %s.%s gagal: %v", filename, format, err)
+	}
+	return nil
+}
+
+func groupName(department string) string {
+	if department == "" {
+		return noDepartment
+	}
+	return department
+}
+
+// ExportLog: GET /export/log?from=2025-12-01&to=2025-12-31&format=csv
+// Setiap scan menjadi satu baris, dialirkan langsung dari database.
+func (h *ExportHandler) ExportLog(c echo.Context) error {
+	request, err := bindExportRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	if request.From == "" {
+		request.From = request.Date
+	}
+	if request.To == "" {
+		request.To = request.From
+	}
+	from, errFrom := sitetime.ParseDate(request.From)
+	to, errTo := sitetime.ParseDate(request.To)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'from' dan 'to' wajib diisi dengan format YYYY-MM-DD",
+		})
+	}
+	// Scan di-stream, tetapi PDF tetap dibangun utuh di memori
+	if to.After(from.AddDate(0, 0, maxAttendanceQueryDays-1)) {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": fmt.Sprintf("Rentang 'from' s/d 'to' maksimal %d hari", maxAttendanceQueryDays),
+		})
+	}
+
+	report := export.Report{
+		Title:      "Log Absensi",
+		Subtitle:   periodLabel(request.From, request.To),
+		GroupLabel: "Departemen",
+		Columns: []export.Column{
+			{Header: "Tanggal", Width: 12, Kind: export.KindDate},
+			{Header: "NIK", Width: 14},
+			{Header: "Nama", Width: 30},
+			{Header: "Waktu Scan", Width: 20, Kind: export.KindDateTime},
+			{Header: "Arah", Width: 8},
+		},
+		Rows: func(emit func(export.Row) error) error {
+			return h.LogRepo.StreamScans(from, to.AddDate(0, 0, 1), request.OrgFilter, func(department string, scan model.RawFingerLog) error {
+				return emit(export.Row{
+					Group:  groupName(department),
+					Values: []interface{}{scan.Timestamp, scan.NIK, scan.FullName, scan.Timestamp, scan.Direction},
+				})
+			})
+		},
+	}
+	return h.writeExport(c, request.Format, "log-absensi-"+request.From+"_"+request.To, report)
+}
+
+// ExportSummary: GET /export/summary?date=2025-12-14&format=xlsx
+func (h *ExportHandler) ExportSummary(c echo.Context) error {
+	request, err := bindExportRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	date, err := sitetime.ParseDate(request.Date)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'date' wajib diisi dengan format YYYY-MM-DD",
+		})
+	}
+
+	summaries, err := h.Service.DailySummary(date, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghitung ringkasan absensi",
+			"error":   err.Error(),
+		})
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return groupName(summaries[i].DepartmentName) < groupName(summaries[j].DepartmentName)
+	})
+
+	report := export.Report{
+		Title:      "Ringkasan Absensi Harian",
+		Subtitle:   "Tanggal " + request.Date,
+		GroupLabel: "Departemen",
+		Columns: []export.Column{
+			{Header: "NIK", Width: 14},
+			{Header: "Nama", Width: 28},
+			{Header: "Shift", Width: 8},
+			{Header: "Masuk", Width: 8, Kind: export.KindTime},
+			{Header: "Pulang", Width: 8, Kind: export.KindTime},
+			{Header: "Jam Kerja", Width: 10, Kind: export.KindNumber},
+			{Header: "Istirahat (mnt)", Width: 10, Kind: export.KindInt},
+			{Header: "Terlambat (mnt)", Width: 10, Kind: export.KindInt},
+			{Header: "Pulang Cepat (mnt)", Width: 10, Kind: export.KindInt},
+			{Header: "Lembur (mnt)", Width: 10, Kind: export.KindInt},
+			{Header: "Status", Width: 12},
+			{Header: "Kategori Catatan", Width: 14},
+			{Header: "Catatan", Width: 30},
+		},
+		Rows: func(emit func(export.Row) error) error {
+			for _, s := range summaries {
+				err := emit(export.Row{
+					Group: groupName(s.DepartmentName),
+					Values: []interface{}{
+						s.NIK, s.FullName, s.ShiftCode, s.CheckIn, s.CheckOut,
+						float64(s.WorkedMinutes) / 60, s.BreakMinutes, s.LateMinutes,
+						s.EarlyLeaveMinutes, s.OvertimeMinutes, s.Status, s.NoteCategory, s.Note,
+					},
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return h.writeExport(c, request.Format, "ringkasan-absensi-"+request.Date, report)
+}
+
+// ExportRecap: GET /export/recap?month=2025-12&format=pdf
+func (h *ExportHandler) ExportRecap(c echo.Context) error {
+	request, err := bindExportRequest(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	month, err := sitetime.ParseMonth(request.Month)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'month' wajib diisi dengan format YYYY-MM",
+		})
+	}
+
+	recaps, err := h.Service.MonthlyRecap(month, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghitung rekap bulanan",
+			"error":   err.Error(),
+		})
+	}
+	sort.SliceStable(recaps, func(i, j int) bool {
+		return groupName(recaps[i].DepartmentName) < groupName(recaps[j].DepartmentName)
+	})
+
+	report := export.Report{
+		Title:      "Rekap Absensi Bulanan",
+		Subtitle:   "Periode " + request.Month,
+		GroupLabel: "Departemen",
+		Columns: []export.Column{
+			{Header: "NIK", Width: 14},
+			{Header: "Nama", Width: 28},
+			{Header: "Hari Terjadwal", Width: 10, Kind: export.KindInt},
+			{Header: "Hadir", Width: 8, Kind: export.KindInt},
+			{Header: "Terlambat (x)", Width: 10, Kind: export.KindInt},
+			{Header: "Terlambat (mnt)", Width: 10, Kind: export.KindInt},
+			{Header: "Tidak Lengkap", Width: 10, Kind: export.KindInt},
+			{Header: "Mangkir", Width: 8, Kind: export.KindInt},
+			{Header: "Izin/Cuti", Width: 8, Kind: export.KindInt},
+			{Header: "Lembur (jam)", Width: 10, Kind: export.KindNumber},
+			{Header: "Jam Kerja", Width: 10, Kind: export.KindNumber},
+		},
+		Rows: func(emit func(export.Row) error) error {
+			for _, r := range recaps {
+				err := emit(export.Row{
+					Group: groupName(r.DepartmentName),
+					Values: []interface{}{
+						r.NIK, r.FullName, r.ScheduledDays, r.DaysPresent, r.LateCount,
+						r.LateMinutes, r.IncompleteDays, r.Absences, r.LeaveDays,
+						r.OvertimeHours, r.WorkedHours,
+					},
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return h.writeExport(c, request.Format, "rekap-absensi-"+request.Month, report)
+}
+
+func periodLabel(from, to string) string {
+	if from == to {
+		return "Tanggal " + from
+	}
+	return "Periode " + from + " s/d " + to
+}
+
+// GetMatrix: GET /reports/matrix?month=2025-12&department_id=1&format=json|xlsx
+// Lembar absensi karyawan x tanggal, format default JSON.
+func (h *ExportHandler) GetMatrix(c echo.Context) error {
+	request := model.ExportRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	if request.Format == "" {
+		request.Format = "json"
+	}
+	if request.Format != "json" && !export.IsSupported(request.Format) {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format tidak didukung, gunakan json, csv, xlsx atau pdf",
+		})
+	}
+	month, err := sitetime.ParseMonth(request.Month)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'month' wajib diisi dengan format YYYY-MM",
+		})
+	}
+
+	matrix, err := h.Service.Matrix(month, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menyusun lembar absensi",
+			"error":   err.Error(),
+		})
+	}
+	if request.Format == "json" {
+		return c.JSON(http.StatusOK, matrix)
+	}
+
+	sort.SliceStable(matrix.Rows, func(i, j int) bool {
+		return groupName(matrix.Rows[i].DepartmentName) < groupName(matrix.Rows[j].DepartmentName)
+	})
+	return h.writeExport(c, request.Format, "lembar-absensi-"+matrix.Month, matrixReport(matrix))
+}
+
+func matrixReport(matrix model.AttendanceMatrix) export.Report {
+	codes := []string{model.CodeHadir, model.CodeSakit, model.CodeIzin, model.CodeAlpha, model.CodeCuti}
+
+	columns := []export.Column{
+		{Header: "NIK", Width: 12},
+		{Header: "Nama", Width: 24},
+	}
+	for _, date := range matrix.Dates {
+		columns = append(columns, export.Column{Header: date[len(date)-2:], Width: 11})
+	}
+	for _, code := range codes {
+		columns = append(columns, export.Column{Header: code, Width: 5, Kind: export.KindInt})
+	}
+
+	return export.Report{
+		Title:      "Lembar Absensi Karyawan",
+		Subtitle:   "Periode " + matrix.Month + " (H=Hadir, S=Sakit, I=Izin, A=Alpha, C=Cuti, L=Libur)",
+		GroupLabel: "Departemen",
+		Columns:    columns,
+		Rows: func(emit func(export.Row) error) error {
+			for _, row := range matrix.Rows {
+				values := []interface{}{row.NIK, row.FullName}
+				for _, cell := range row.Cells {
+					values = append(values, cell.Text)
+				}
+				for _, code := range codes {
+					values = append(values, row.Totals[code])
+				}
+				if err := emit(export.Row{Group: groupName(row.DepartmentName), Values: values}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
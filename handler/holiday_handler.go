@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"Steril-App/model"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HolidayHandler struct {
+	Service *service.HolidayService
+}
+
+func NewHolidayHandler(service *service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{Service: service}
+}
+
+func holidayError(c echo.Context, message string, err error) error {
+	if errors.Is(err, service.ErrInvalidHoliday) {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, echo.Map{
+		"message": message,
+		"error":   err.Error(),
+	})
+}
+
+// CreateHoliday: POST /holidays {"date": "2025-12-25", "name": "Natal", "kind": "public"}
+func (h *HolidayHandler) CreateHoliday(c echo.Context) error {
+	request := model.CreateHolidayRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	holidays, skipped, err := h.Service.CreateHoliday(&request)
+	if err != nil {
+		return holidayError(c, "Gagal menyimpan hari libur", err)
+	}
+	return c.JSON(http.StatusCreated, echo.Map{
+		"holidays": holidays,
+		"skipped":  skipped,
+	})
+}
+
+// GetHolidays: GET /holidays?year=2025&department_id=1&site=CKR
+func (h *HolidayHandler) GetHolidays(c echo.Context) error {
+	filter := model.HolidayFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+
+	holidays, err := h.Service.Repo.GetHolidays(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil hari libur",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, holidays)
+}
+
+func (h *HolidayHandler) DeleteHoliday(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID hari libur tidak valid"})
+	}
+
+	err = h.Service.Repo.DeleteHoliday(id)
+	if errors.Is(err, repository.ErrHolidayNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghapus hari libur",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Hari libur dihapus"})
+}
+
+// ImportHolidays: POST /holidays/import (multipart) file=<.ics>, kind, department_id, site
+func (h *HolidayHandler) ImportHolidays(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "File .ics wajib diunggah pada field 'file'"})
+	}
+
+	var departmentID *int
+	if value := c.FormValue("department_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"message": "department_id tidak valid"})
+		}
+		departmentID = &id
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Gagal membaca file",
+			"error":   err.Error(),
+		})
+	}
+	defer file.Close()
+
+	result, err := h.Service.ImportICS(file, c.FormValue("kind"), departmentID, c.FormValue("site"))
+	if err != nil {
+		return holidayError(c, "Gagal mengimpor hari libur", err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
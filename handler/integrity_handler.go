@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IntegrityHandler struct {
+	Service *service.IntegrityService
+}
+
+func NewIntegrityHandler(service *service.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{Service: service}
+}
+
+// Verify: GET /integrity/verify, menelusuri rantai hash fingerlog dan audit_log.
+// Status 200 juga untuk rantai yang rusak; lihat field "valid" dan "broken".
+func (h *IntegrityHandler) Verify(c echo.Context) error {
+	report, err := h.Service.Verify()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal memverifikasi rantai",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetCheckpoint: GET /integrity/checkpoints/:date, ekspor checkpoint bertanda tangan.
+// Checkpoint dibuat saat itu juga jika belum ada dan harinya sudah berakhir.
+func (h *IntegrityHandler) GetCheckpoint(c echo.Context) error {
+	day, err := sitetime.ParseDate(c.Param("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Tanggal harus berformat YYYY-MM-DD"})
+	}
+
+	checkpoint, err := h.Service.Checkpoint(day)
+	switch {
+	case errors.Is(err, service.ErrCheckpointTooEarly):
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	case errors.Is(err, service.ErrSigningDisabled):
+		return c.JSON(http.StatusServiceUnavailable, echo.Map{"message": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal membuat checkpoint",
+			"error":   err.Error(),
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="checkpoint-`+checkpoint.Date+`.json"`)
+	return c.JSON(http.StatusOK, checkpoint)
+}
+
+// GetPublicKey: GET /integrity/public-key, untuk memverifikasi checkpoint di luar aplikasi
+func (h *IntegrityHandler) GetPublicKey(c echo.Context) error {
+	publicKey := h.Service.PublicKey()
+	if publicKey == "" {
+		return c.JSON(http.StatusServiceUnavailable, echo.Map{"message": service.ErrSigningDisabled.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{"algorithm": "ed25519", "public_key": publicKey})
+}
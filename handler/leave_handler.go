@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LeaveHandler struct {
+	Service *service.LeaveService
+}
+
+func NewLeaveHandler(service *service.LeaveService) *LeaveHandler {
+	return &LeaveHandler{Service: service}
+}
+
+func (h *LeaveHandler) GetLeaveTypes(c echo.Context) error {
+	return c.JSON(http.StatusOK, model.LeaveTypes)
+}
+
+// CreateRequest: POST /leave/requests
+// {"nik": "...", "leave_type": "annual", "start_date": "2025-12-22", "end_date": "2025-12-24", ...}
+func (h *LeaveHandler) CreateRequest(c echo.Context) error {
+	request := model.CreateLeaveRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	leave, err := h.Service.CreateRequest(&request)
+	if err != nil {
+		return requestError(c, "Gagal menyimpan pengajuan cuti", err)
+	}
+	return c.JSON(http.StatusCreated, leave)
+}
+
+// GetRequests: GET /leave/requests?nik=...&status=submitted&from=2025-12-01&to=2025-12-31
+func (h *LeaveHandler) GetRequests(c echo.Context) error {
+	filter := model.LeaveRequestFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	for _, value := range []string{filter.From, filter.To} {
+		if value == "" {
+			continue
+		}
+		if _, err := sitetime.ParseDate(value); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Parameter 'from' dan 'to' harus berformat YYYY-MM-DD",
+			})
+		}
+	}
+
+	requests, err := h.Service.Repo.ListRequests(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil pengajuan cuti",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, requests)
+}
+
+// ApproveRequest: POST /leave/requests/:id/approve, token atasan atau HR di header Authorization
+func (h *LeaveHandler) ApproveRequest(c echo.Context) error {
+	return h.decide(c, h.Service.Approve)
+}
+
+// RejectRequest: POST /leave/requests/:id/reject {"note": "..."}, token atasan atau HR di header Authorization
+func (h *LeaveHandler) RejectRequest(c echo.Context) error {
+	return h.decide(c, h.Service.Reject)
+}
+
+func (h *LeaveHandler) decide(c echo.Context, decide func(int, *model.DecideRequest) (model.LeaveRequest, error)) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID pengajuan tidak valid"})
+	}
+	request := model.DecideRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	// Pemutus dari token (RequireActor); decided_by di body diabaikan
+	request.DecidedBy = authenticatedActor(c)
+
+	leave, err := decide(id, &request)
+	if err != nil {
+		return requestError(c, "Gagal memproses pengajuan cuti", err)
+	}
+	return c.JSON(http.StatusOK, leave)
+}
+
+// GetBalance: GET /users/:nik/leave-balance?year=2025 (default tahun berjalan)
+func (h *LeaveHandler) GetBalance(c echo.Context) error {
+	year := sitetime.Now().Year()
+	if value := c.QueryParam("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter 'year' tidak valid"})
+		}
+		year = parsed
+	}
+
+	balance, err := h.Service.Repo.GetBalance(c.Param("nik"), year)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghitung saldo cuti",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, balance)
+}
+
+// SetBalance: PUT /users/:nik/leave-balance {"year": 2025, "entitled_days": 12},
+// token HR atau atasan di header Authorization
+func (h *LeaveHandler) SetBalance(c echo.Context) error {
+	request := model.SetLeaveBalanceRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	balance, err := h.Service.SetBalance(c.Param("nik"), authenticatedActor(c), &request)
+	if err != nil {
+		return requestError(c, "Gagal menyimpan hak cuti", err)
+	}
+	return c.JSON(http.StatusOK, balance)
+}
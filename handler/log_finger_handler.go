@@ -2,26 +2,40 @@ package handler
 
 import (
 	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
 	"Steril-App/model"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 type LogFingerHandler struct {
-	Repo *repository.FingerLogRepository
+	Repo       *repository.FingerLogRepository
+	Attendance *service.AttendanceService // Pengelompokan scan per tanggal kerja
+	Logs       *service.FingerLogService  // Log terhapus dan pemulihan
+	Notes      *service.NoteService       // Catatan massal dan penghapusan beserta lampiran
 }
 
-func NewLogFingerHanlere(repo *repository.FingerLogRepository) *LogFingerHandler {
-	return &LogFingerHandler{Repo: repo}
+func NewLogFingerHanlere(repo *repository.FingerLogRepository, attendance *service.AttendanceService, logs *service.FingerLogService, notes *service.NoteService) *LogFingerHandler {
+	return &LogFingerHandler{Repo: repo, Attendance: attendance, Logs: logs, Notes: notes}
 }
 
 func (h *LogFingerHandler) GetFingerLog(c echo.Context) error {
 	request := model.FingerLogRequest{}
 	c.Bind(&request)
-	result, err := h.Repo.GetFingerLog(request.Date)
+	day, err := sitetime.ParseDate(request.Date)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Parameter 'date' wajib diisi dengan format YYYY-MM-DD",
+		})
+	}
+	// Dikelompokkan per tanggal kerja: scan pulang shift malam ikut tanggal masuknya
+	result, err := h.Attendance.FingerLogs(day, day, "", request.OrgFilter)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
 			"error": err,
@@ -46,9 +60,24 @@ func (h *LogFingerHandler) SaveNote(c echo.Context) error {
             "message": "NIK dan Tanggal wajib diisi",
         })
     }
+    if request.Category == "" {
+        request.Category = model.NoteOther
+    }
+    if !model.IsNoteCategory(request.Category) {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Kategori harus sick, permit, duty_outside, late_reason atau other",
+        })
+    }
+
+    audit, ok := auditContext(c, request.Reason)
+    if !ok {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Alasan perubahan wajib diisi",
+        })
+    }
 
     // 3. Panggil Repository
-    err := h.Repo.SaveUserNote(request.NIK, request.Date, request.Note)
+    err := h.Repo.SaveUserNote(request.NIK, request.Date, request.Category, request.Note, audit)
     if err != nil {
         return c.JSON(http.StatusInternalServerError, map[string]interface{}{
             "message": "Gagal menyimpan catatan",
@@ -63,19 +92,38 @@ func (h *LogFingerHandler) SaveNote(c echo.Context) error {
     })
 }
 
+// maxNoteQueryDays: rentang terpanjang GET /notes, sama dengan batas /notes/bulk
+const maxNoteQueryDays = 31
+
 // GetNotes: Dipanggil saat load awal halaman atau saat ganti tanggal
 // Contoh URL: GET /notes?date=2025-12-14
+//             GET /notes?from=2025-12-01&to=2025-12-31&nik=123
 func (h *LogFingerHandler) GetNotes(c echo.Context) error {
-    // 1. Ambil parameter date dari URL
-    date := c.QueryParam("date")
-    if date == "" {
+    // 1. Ambil parameter dari URL; date = satu tanggal
+    query := model.NoteQuery{}
+    if err := c.Bind(&query); err != nil {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Parameter tidak valid",
+        })
+    }
+    if query.Date != "" {
+        query.From, query.To = query.Date, query.Date
+    }
+    from, errFrom := sitetime.ParseDate(query.From)
+    to, errTo := sitetime.ParseDate(query.To)
+    if errFrom != nil || errTo != nil || to.Before(from) {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Parameter 'date' atau 'from' dan 'to' diperlukan dengan format YYYY-MM-DD",
+        })
+    }
+    if to.After(from.AddDate(0, 0, maxNoteQueryDays-1)) {
         return c.JSON(http.StatusBadRequest, map[string]interface{}{
-            "message": "Parameter 'date' diperlukan",
+            "message": fmt.Sprintf("Rentang 'from' s/d 'to' maksimal %d hari", maxNoteQueryDays),
         })
     }
 
     // 2. Panggil Repository
-    notes, err := h.Repo.GetNotesByDate(date)
+    notes, err := h.Repo.GetNotesBetween(from, to, query.NIK)
     if err != nil {
         return c.JSON(http.StatusInternalServerError, map[string]interface{}{
             "message": "Gagal mengambil data catatan",
@@ -87,6 +135,105 @@ func (h *LogFingerHandler) GetNotes(c echo.Context) error {
     return c.JSON(http.StatusOK, notes)
 }
 
+// DeleteNote: DELETE /notes?nik=123&date=2025-12-14&reason=...
+// (atau body JSON dengan field yang sama)
+func (h *LogFingerHandler) DeleteNote(c echo.Context) error {
+	request := model.DeleteNoteRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if request.NIK == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "NIK dan Tanggal wajib diisi",
+		})
+	}
+	if _, err := sitetime.ParseDate(request.Date); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format date harus YYYY-MM-DD",
+		})
+	}
+	audit, ok := auditContext(c, request.Reason)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Alasan perubahan wajib diisi",
+		})
+	}
+
+	// Lampiran catatan ikut terhapus
+	err := h.Notes.DeleteNote(request.NIK, request.Date, audit)
+	if errors.Is(err, repository.ErrNoteNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{"message": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Gagal menghapus catatan",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, model.SuccessResponse{
+		Message: "Catatan berhasil dihapus",
+		Status:  http.StatusOK,
+	})
+}
+
+// BulkNotes: POST /notes/bulk, satu catatan untuk banyak karyawan dan/atau tanggal
+// {"department_id": 3, "from": "2025-12-15", "category": "duty_outside", "note": "Training K3", ...}
+// {"niks": ["123"], "from": "2025-12-15", "to": "2025-12-19", "category": "duty_outside", ...}
+func (h *LogFingerHandler) BulkNotes(c echo.Context) error {
+	request := model.BulkNoteRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	audit, ok := auditContext(c, request.Reason)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Alasan perubahan wajib diisi",
+		})
+	}
+
+	result, err := h.Notes.ApplyBulk(&request, audit)
+	if errors.Is(err, service.ErrInvalidNote) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"message": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Gagal menyimpan catatan",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetNoteHistory: GET /notes/history?nik=123&date=2025-12-14, semua revisi catatan
+func (h *LogFingerHandler) GetNoteHistory(c echo.Context) error {
+    nik, date := c.QueryParam("nik"), c.QueryParam("date")
+    if nik == "" || date == "" {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Parameter 'nik' dan 'date' diperlukan",
+        })
+    }
+    if _, err := sitetime.ParseDate(date); err != nil {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Format date harus YYYY-MM-DD",
+        })
+    }
+
+    revisions, err := h.Repo.GetNoteRevisions(nik, date)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+            "message": "Gagal mengambil riwayat catatan",
+            "error":   err.Error(),
+        })
+    }
+    return c.JSON(http.StatusOK, revisions)
+}
+
 func (h *LogFingerHandler) AddManualFingerLog(c echo.Context) error {
     // 1. Bind request
     request := model.AddManualFingerLogRequest{}
@@ -104,20 +251,15 @@ func (h *LogFingerHandler) AddManualFingerLog(c echo.Context) error {
             "message": "NIK dan Timestamp harus diisi",
         })
     }
-
-    // 3. Parsing String Waktu dengan LOKASI (Zona Waktu)
-    layout := "2006-01-02 15:04:05"
-    
-    // Load lokasi Asia/Jakarta
-    loc, err := time.LoadLocation("Asia/Jakarta")
-    if err != nil {
-        // Fallback jika server tidak punya data tz (misal di docker alpine belum install tzdata)
-        // Kita paksa offset +7
-        loc = time.FixedZone("WIB", 7*60*60) 
+    audit, ok := auditContext(c, request.Reason)
+    if !ok {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Alasan koreksi wajib diisi",
+        })
     }
 
-    // Gunakan ParseInLocation, bukan Parse biasa
-    parsedTime, err := time.ParseInLocation(layout, request.Timestamp, loc)
+    // 3. Parsing String Waktu pada zona site (SITE_TIMEZONE)
+    parsedTime, err := sitetime.ParseDateTime(request.Timestamp)
     
     if err != nil {
         return c.JSON(http.StatusBadRequest, map[string]interface{}{
@@ -126,11 +268,18 @@ func (h *LogFingerHandler) AddManualFingerLog(c echo.Context) error {
         })
     }
 
-    // 4. Panggil Repository Manual
-    // Go sekarang tahu bahwa parsedTime adalah WIB. 
-    // Saat dikirim ke Postgres, Go akan otomatis mengonversinya ke UTC yang benar 
-    // (misal jam 08:00 WIB -> dikirim sebagai 01:00 UTC).
-    err = h.Repo.AddManualFingerLog(request.NIK, parsedTime)
+    // 4. Simpan lewat service (hanya HR/admin, selain itu lewat /corrections)
+    // parsedTime sudah membawa zona site, driver mengirimnya sebagai instan yang benar
+    direction := service.NormalizeDirection(request.Direction)
+    if request.Direction != "" && direction == "" {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{
+            "message": "Arah scan harus IN atau OUT",
+        })
+    }
+    err = h.Logs.AddManual(request.NIK, parsedTime, direction, audit)
+    if errors.Is(err, service.ErrNotEditor) {
+        return c.JSON(http.StatusForbidden, map[string]interface{}{"message": err.Error()})
+    }
     if err != nil {
         return c.JSON(http.StatusInternalServerError, map[string]interface{}{
             "message": "Gagal menambahkan data manual",
@@ -164,45 +313,31 @@ func (h *LogFingerHandler) DeleteFingerLog(c echo.Context) error {
     if request.NIK == "" || request.Timestamp == "" {
         return c.JSON(http.StatusBadRequest, map[string]interface{}{"message": "NIK dan Timestamp harus diisi"})
     }
-
-    // 2. PARSING WAKTU (MULTI-FORMAT)
-    // Kita siapkan beberapa kemungkinan format agar fleksibel
-    layouts := []string{
-        "2006-01-02 15:04:05.000 -0700", // Format DETAIL (sesuai data kamu: 2025-11-27 17:18:23.838 +0700)
-        "2006-01-02 15:04:05.000",       // Format detail tanpa timezone
-        "2006-01-02 15:04:05",           // Format standar (detik 00)
-        time.RFC3339,                    // Format ISO standar (2025-11-27T17:...)
-    }
-
-    var parsedTime time.Time
-    parsedSuccess := false
-
-    for _, layout := range layouts {
-        // Coba parse
-        t, err := time.Parse(layout, request.Timestamp)
-        if err == nil {
-            parsedTime = t
-            parsedSuccess = true
-            fmt.Println("Berhasil parse dengan format:", layout) // Debug
-            break
-        }
+    audit, ok := auditContext(c, request.Reason)
+    if !ok {
+        return c.JSON(http.StatusBadRequest, map[string]interface{}{"message": "Alasan penghapusan wajib diisi"})
     }
 
-    if !parsedSuccess {
+    // 2. PARSING WAKTU (MULTI-FORMAT)
+    // Nilai tanpa offset dibaca sebagai jam dinding zona site
+    parsedTime, err := sitetime.ParseDateTime(request.Timestamp)
+    if err != nil {
         fmt.Println("Gagal parsing waktu. String:", request.Timestamp)
         return c.JSON(http.StatusBadRequest, map[string]interface{}{
             "message": "Format waktu tidak dikenali. Pastikan format YYYY-MM-DD HH:mm:ss.SSS +0700",
         })
     }
 
-    // 3. Panggil Repository
+    // 3. Hapus lewat service (hanya HR/admin, selain itu lewat /corrections)
     // Pastikan parsedTime ini memiliki presisi milidetik yang sama dengan DB
-    err := h.Repo.DeleteFingerLog(request.NIK, parsedTime)
+    err = h.Logs.DeleteByTimestamp(request.NIK, parsedTime, audit)
+    if errors.Is(err, service.ErrNotEditor) {
+        return c.JSON(http.StatusForbidden, map[string]interface{}{"message": err.Error()})
+    }
     if err != nil {
         fmt.Println("Repository Error:", err.Error()) // Debug di terminal
         
-        // Sesuaikan pesan error ini dengan return dari repo kamu
-        if err.Error() == "data tidak ditemukan atau sudah terhapus" {
+        if errors.Is(err, repository.ErrFingerLogNotFound) {
             return c.JSON(http.StatusNotFound, map[string]interface{}{
                 "message": "Data tidak ditemukan. Kemungkinan selisih milidetik.",
                 "debug_time": parsedTime.String(),
@@ -218,4 +353,204 @@ func (h *LogFingerHandler) DeleteFingerLog(c echo.Context) error {
     return c.JSON(http.StatusOK, map[string]interface{}{
         "message": "Berhasil menghapus log finger",
     })
-}
\ No newline at end of file
+}
+
+// GetAttendance: GET /attendance?from=2025-12-01&to=2025-12-31&nik=&department=&page=1&limit=50
+func (h *LogFingerHandler) GetAttendance(c echo.Context) error {
+	query := model.AttendanceQuery{}
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Parameter tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	return h.queryAttendance(c, query)
+}
+
+// GetUserAttendance: GET /users/:nik/attendance?from=&to=&page=&limit=
+func (h *LogFingerHandler) GetUserAttendance(c echo.Context) error {
+	query := model.AttendanceQuery{}
+	if err := c.Bind(&query); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Parameter tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	query.NIK = c.Param("nik")
+	return h.queryAttendance(c, query)
+}
+
+// maxAttendanceQueryDays: rentang terpanjang GET /attendance, /users/:nik/attendance
+// dan /export/log
+const maxAttendanceQueryDays = 92
+
+func (h *LogFingerHandler) queryAttendance(c echo.Context, query model.AttendanceQuery) error {
+	from, errFrom := sitetime.ParseDate(query.From)
+	to, errTo := sitetime.ParseDate(query.To)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Parameter 'from' dan 'to' wajib diisi dengan format YYYY-MM-DD",
+		})
+	}
+	// Scan seluruh rentang dikelompokkan di memori, jadi rentang dibatasi
+	if to.After(from.AddDate(0, 0, maxAttendanceQueryDays-1)) {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": fmt.Sprintf("Rentang 'from' s/d 'to' maksimal %d hari", maxAttendanceQueryDays),
+		})
+	}
+	if query.Department != 0 && query.DepartmentID == 0 {
+		query.DepartmentID = query.Department
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 500 {
+		query.Limit = 50
+	}
+
+	// Paginasi per kelompok (NIK, tanggal kerja), bukan per scan
+	groups, err := h.Attendance.FingerLogs(from, to, query.NIK, query.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Gagal mengambil data absensi",
+			"error":   err.Error(),
+		})
+	}
+	total := len(groups)
+	// page dibandingkan dulu dengan jumlah halaman agar perkalian tidak overflow
+	first := total
+	if query.Page-1 < (total+query.Limit-1)/query.Limit {
+		first = (query.Page - 1) * query.Limit
+	}
+	data := groups[first:min(first+query.Limit, total)]
+
+	return c.JSON(http.StatusOK, model.PagedFingerLogResult{
+		Data:  data,
+		Page:  query.Page,
+		Limit: query.Limit,
+		Total: total,
+	})
+}
+
+// DeleteAttendance: DELETE /attendance/:id, hapus lunak satu log berdasarkan ID.
+// reason dikirim di body JSON atau query string; khusus HR atau admin (token Authorization).
+func (h *LogFingerHandler) DeleteAttendance(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID log tidak valid"})
+	}
+	request := model.DeleteAttendanceRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Format data tidak valid", "error": err.Error()})
+	}
+	audit, ok := auditContext(c, request.Reason)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Alasan penghapusan wajib diisi"})
+	}
+
+	deleted, err := h.Logs.SoftDelete(id, audit)
+	if errors.Is(err, service.ErrNotEditor) {
+		return c.JSON(http.StatusForbidden, echo.Map{"message": err.Error()})
+	}
+	if errors.Is(err, repository.ErrFingerLogNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"message": "Gagal menghapus data", "error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Berhasil menghapus log finger", "data": deleted})
+}
+
+// CorrectAttendance: PATCH /attendance/:id, koreksi jam scan. Log lama dihapus
+// lunak dan diganti log baru; ID log baru dikembalikan di "data". Khusus HR atau admin.
+func (h *LogFingerHandler) CorrectAttendance(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID log tidak valid"})
+	}
+	request := model.CorrectAttendanceRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Format data tidak valid", "error": err.Error()})
+	}
+	audit, ok := auditContext(c, request.Reason)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Alasan koreksi wajib diisi"})
+	}
+	timestamp, err := sitetime.ParseDateTime(request.Timestamp)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	direction := service.NormalizeDirection(request.Direction)
+	if request.Direction != "" && direction == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Arah scan harus IN atau OUT"})
+	}
+
+	corrected, err := h.Logs.Correct(id, timestamp, direction, audit)
+	if errors.Is(err, service.ErrNotEditor) {
+		return c.JSON(http.StatusForbidden, echo.Map{"message": err.Error()})
+	}
+	if errors.Is(err, repository.ErrFingerLogNotFound) {
+		return c.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"message": "Gagal mengoreksi data", "error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Berhasil mengoreksi log finger", "data": corrected})
+}
+
+// GetDeletedAttendance: GET /attendance/deleted?from=&to=&nik=
+// Tanpa from/to, dikembalikan log yang dihapus selama masa retensi.
+func (h *LogFingerHandler) GetDeletedAttendance(c echo.Context) error {
+	filter := model.DeletedFingerLogFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	var from, to time.Time
+	if filter.From != "" || filter.To != "" {
+		var errFrom, errTo error
+		from, errFrom = sitetime.ParseDate(filter.From)
+		to, errTo = sitetime.ParseDate(filter.To)
+		if errFrom != nil || errTo != nil || to.Before(from) {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Parameter 'from' dan 'to' harus berformat YYYY-MM-DD",
+			})
+		}
+	}
+
+	logs, err := h.Logs.ListDeleted(filter.NIK, from, to)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"message": "Gagal mengambil log terhapus", "error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, logs)
+}
+
+// RestoreAttendance: POST /attendance/:id/restore {"reason": "..."} dengan header
+// Authorization: Bearer <token>; khusus admin dan selama masa retensi
+func (h *LogFingerHandler) RestoreAttendance(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID log tidak valid"})
+	}
+	request := model.RestoreAttendanceRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Format data tidak valid", "error": err.Error()})
+	}
+	// Actor dari token dipakai juga untuk cek admin
+	audit, ok := auditContext(c, request.Reason)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Alasan pemulihan wajib diisi"})
+	}
+
+	restored, err := h.Logs.Restore(id, audit)
+	switch {
+	case errors.Is(err, service.ErrNotAdmin):
+		return c.JSON(http.StatusForbidden, echo.Map{"message": err.Error()})
+	case errors.Is(err, repository.ErrFingerLogNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"message": "Log terhapus tidak ditemukan"})
+	case errors.Is(err, repository.ErrRestoreExpired), errors.Is(err, repository.ErrFingerLogReplaced):
+		return c.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, echo.Map{"message": "Gagal memulihkan log", "error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Berhasil memulihkan log finger", "data": restored})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"Steril-App/internal/blobstore"
+	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"Steril-App/model"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NoteAttachmentHandler struct {
+	Service *service.NoteService
+}
+
+func NewNoteAttachmentHandler(service *service.NoteService) *NoteAttachmentHandler {
+	return &NoteAttachmentHandler{Service: service}
+}
+
+// attachmentError: validasi -> 400, catatan/lampiran tidak ada -> 404, selain itu 500
+func attachmentError(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidNote):
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	case errors.Is(err, repository.ErrNoteNotFound), errors.Is(err, repository.ErrAttachmentNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, echo.Map{
+		"message": message,
+		"error":   err.Error(),
+	})
+}
+
+// UploadBodyLimit: batas body untuk middleware.BodyLimit pada route upload,
+// yaitu MaxAttachmentBytes ditambah 1 MB untuk field form dan overhead multipart
+func (h *NoteAttachmentHandler) UploadBodyLimit() string {
+	return strconv.FormatInt(h.Service.MaxAttachmentBytes>>20+1, 10) + "MiB"
+}
+
+// Upload: POST /notes/attachments (multipart) file=<pdf/gambar>, nik, date, reason.
+// Catatan untuk nik dan date harus sudah ada.
+func (h *NoteAttachmentHandler) Upload(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "File wajib diunggah pada field 'file'"})
+	}
+	if fileHeader.Size > h.Service.MaxAttachmentBytes {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Ukuran file maksimal " + strconv.FormatInt(h.Service.MaxAttachmentBytes>>20, 10) + " MB",
+		})
+	}
+	audit, ok := auditContext(c, c.FormValue("reason"))
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Alasan perubahan wajib diisi"})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Gagal membaca file",
+			"error":   err.Error(),
+		})
+	}
+	defer file.Close()
+
+	attachment, err := h.Service.AddAttachment(c.FormValue("nik"), c.FormValue("date"), fileHeader.Filename, file, audit)
+	if err != nil {
+		return attachmentError(c, "Gagal menyimpan lampiran", err)
+	}
+	return c.JSON(http.StatusCreated, attachment)
+}
+
+// List: GET /notes/attachments?nik=123&date=2025-12-14
+func (h *NoteAttachmentHandler) List(c echo.Context) error {
+	nik, date := c.QueryParam("nik"), c.QueryParam("date")
+	if nik == "" || date == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter 'nik' dan 'date' diperlukan"})
+	}
+
+	attachments, err := h.Service.Attachments.ListAttachments(nik, date)
+	if err != nil {
+		return attachmentError(c, "Gagal mengambil lampiran", err)
+	}
+	return c.JSON(http.StatusOK, attachments)
+}
+
+// Download: GET /notes/attachments/:id. Isi file diverifikasi terhadap checksum
+// SHA-256 di metadata sebelum dikirim; checksum juga dikirim di header
+// X-Checksum-Sha256. File hilang -> 410, isi tidak cocok -> 500.
+func (h *NoteAttachmentHandler) Download(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID lampiran tidak valid"})
+	}
+
+	attachment, content, err := h.Service.ReadAttachment(id)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return c.JSON(http.StatusGone, echo.Map{"message": "File lampiran tidak ada di penyimpanan"})
+	}
+	if err != nil {
+		return attachmentError(c, "Gagal mengambil lampiran", err)
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	header.Set("X-Checksum-Sha256", attachment.SHA256)
+	header.Set("ETag", `"`+attachment.SHA256+`"`)
+	return c.Blob(http.StatusOK, attachment.ContentType, content)
+}
+
+// Delete: DELETE /notes/attachments/:id?reason=... (atau body JSON)
+func (h *NoteAttachmentHandler) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID lampiran tidak valid"})
+	}
+	request := model.DeleteAttachmentRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	audit, ok := auditContext(c, request.Reason)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Alasan perubahan wajib diisi"})
+	}
+
+	if err := h.Service.DeleteAttachment(id, audit); err != nil {
+		return attachmentError(c, "Gagal menghapus lampiran", err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Lampiran dihapus"})
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OrganizationHandler struct {
+	Repo *repository.OrganizationRepository
+}
+
+func NewOrganizationHandler(repo *repository.OrganizationRepository) *OrganizationHandler {
+	return &OrganizationHandler{Repo: repo}
+}
+
+func (h *OrganizationHandler) CreateDepartment(c echo.Context) error {
+	request := model.CreateDepartmentRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if request.Code == "" || request.Name == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Kode dan nama departemen wajib diisi",
+		})
+	}
+
+	dept, err := h.Repo.CreateDepartment(&request)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menyimpan departemen",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusCreated, dept)
+}
+
+func (h *OrganizationHandler) GetDepartments(c echo.Context) error {
+	departments, err := h.Repo.GetDepartments()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil data departemen",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, departments)
+}
+
+func (h *OrganizationHandler) CreateLine(c echo.Context) error {
+	request := model.CreateLineRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if request.DepartmentID == 0 || request.Code == "" || request.Name == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Departemen, kode dan nama line wajib diisi",
+		})
+	}
+
+	line, err := h.Repo.CreateLine(&request)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menyimpan line",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusCreated, line)
+}
+
+// GetLines: GET /lines?department_id=1
+func (h *OrganizationHandler) GetLines(c echo.Context) error {
+	departmentID := 0
+	if v := c.QueryParam("department_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Parameter 'department_id' tidak valid",
+			})
+		}
+		departmentID = id
+	}
+
+	lines, err := h.Repo.GetLines(departmentID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil data line",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, lines)
+}
+
+// AssignUser: POST /users/:nik/assignments
+func (h *OrganizationHandler) AssignUser(c echo.Context) error {
+	nik := c.Param("nik")
+	request := model.AssignUserRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if request.DepartmentID == 0 || request.EffectiveFrom == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Departemen dan tanggal mulai berlaku wajib diisi",
+		})
+	}
+
+	effectiveFrom, err := sitetime.ParseDate(request.EffectiveFrom)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format tanggal salah. Gunakan format: YYYY-MM-DD",
+		})
+	}
+	var effectiveTo *time.Time
+	if request.EffectiveTo != "" {
+		t, err := sitetime.ParseDate(request.EffectiveTo)
+		if err != nil || t.Before(effectiveFrom) {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Tanggal akhir berlaku tidak valid",
+			})
+		}
+		effectiveTo = &t
+	}
+
+	assignment, err := h.Repo.AssignUser(nik, &request, effectiveFrom, effectiveTo)
+	if errors.Is(err, repository.ErrAssignmentOverlap) {
+		return c.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menyimpan penugasan user",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusCreated, assignment)
+}
+
+// GetAssignments: GET /users/:nik/assignments
+func (h *OrganizationHandler) GetAssignments(c echo.Context) error {
+	assignments, err := h.Repo.GetAssignments(c.Param("nik"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil riwayat penugasan",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, assignments)
+}
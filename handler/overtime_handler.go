@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OvertimeHandler struct {
+	Service *service.OvertimeService
+}
+
+func NewOvertimeHandler(service *service.OvertimeService) *OvertimeHandler {
+	return &OvertimeHandler{Service: service}
+}
+
+// CreateRequest: POST /overtime/requests
+func (h *OvertimeHandler) CreateRequest(c echo.Context) error {
+	request := model.CreateOvertimeRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	overtime, err := h.Service.CreateRequest(&request)
+	if err != nil {
+		return requestError(c, "Gagal menyimpan pengajuan lembur", err)
+	}
+	return c.JSON(http.StatusCreated, overtime)
+}
+
+// GetRequests: GET /overtime/requests?date=2025-12-14&nik=...&status=submitted
+func (h *OvertimeHandler) GetRequests(c echo.Context) error {
+	filter := model.OvertimeRequestFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	if filter.Date != "" {
+		if _, err := sitetime.ParseDate(filter.Date); err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter 'date' harus berformat YYYY-MM-DD"})
+		}
+	}
+
+	requests, err := h.Service.Repo.ListRequests(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil pengajuan lembur",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, requests)
+}
+
+// ApproveRequest: POST /overtime/requests/:id/approve, token atasan atau HR di header Authorization
+func (h *OvertimeHandler) ApproveRequest(c echo.Context) error {
+	return h.decide(c, h.Service.Approve)
+}
+
+// RejectRequest: POST /overtime/requests/:id/reject {"note": "..."}, token atasan atau HR di header Authorization
+func (h *OvertimeHandler) RejectRequest(c echo.Context) error {
+	return h.decide(c, h.Service.Reject)
+}
+
+func (h *OvertimeHandler) decide(c echo.Context, decide func(int, *model.DecideRequest) (model.OvertimeRequest, error)) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "ID pengajuan tidak valid"})
+	}
+	request := model.DecideRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	// Pemutus dari token (RequireActor); decided_by di body diabaikan
+	request.DecidedBy = authenticatedActor(c)
+
+	overtime, err := decide(id, &request)
+	if err != nil {
+		return requestError(c, "Gagal memproses pengajuan lembur", err)
+	}
+	return c.JSON(http.StatusOK, overtime)
+}
+
+// GetDaily: GET /overtime/daily?date=2025-12-14&department_id=1
+func (h *OvertimeHandler) GetDaily(c echo.Context) error {
+	request := model.OvertimeReportRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	date, err := sitetime.ParseDate(request.Date)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'date' wajib diisi dengan format YYYY-MM-DD",
+		})
+	}
+
+	days, err := h.Service.Daily(date, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghitung lembur harian",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, days)
+}
+
+// GetMonthly: GET /overtime/monthly?month=2025-12&department_id=1
+func (h *OvertimeHandler) GetMonthly(c echo.Context) error {
+	request := model.OvertimeReportRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	month, err := sitetime.ParseMonth(request.Month)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'month' wajib diisi dengan format YYYY-MM",
+		})
+	}
+
+	totals, err := h.Service.Monthly(month, request.OrgFilter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghitung lembur bulanan",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, totals)
+}
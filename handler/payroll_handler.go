@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"Steril-App/internal/payroll"
+	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PayrollHandler struct {
+	Service *service.PayrollService
+}
+
+func NewPayrollHandler(service *service.PayrollService) *PayrollHandler {
+	return &PayrollHandler{Service: service}
+}
+
+// payrollError: validasi -> 400, template tidak ada -> 404,
+// karyawan tanpa ID payroll -> 409, selain itu 500
+func payrollError(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidPayroll):
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	case errors.Is(err, repository.ErrTemplateNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	case errors.Is(err, service.ErrUnmappedEmployees):
+		return c.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, echo.Map{
+		"message": message,
+		"error":   err.Error(),
+	})
+}
+
+// GetTemplates: GET /payroll/templates, beserta daftar field yang tersedia
+func (h *PayrollHandler) GetTemplates(c echo.Context) error {
+	templates, err := h.Service.Repo.ListTemplates()
+	if err != nil {
+		return payrollError(c, "Gagal mengambil template payroll", err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"templates": templates, "fields": payroll.Fields()})
+}
+
+// GetTemplate: GET /payroll/templates/:name
+func (h *PayrollHandler) GetTemplate(c echo.Context) error {
+	template, err := h.Service.Repo.GetTemplate(c.Param("name"))
+	if err != nil {
+		return payrollError(c, "Gagal mengambil template payroll", err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// SaveTemplate: PUT /payroll/templates/:name
+// {"delimiter": ";", "decimal_separator": ",", "date_format": "DD/MM/YYYY", "identifier": "mapped",
+// "columns": [{"header": "EmpNo", "field": "employee_id"}, {"header": "OT15", "field": "overtime_x1_5_hours"}]}
+func (h *PayrollHandler) SaveTemplate(c echo.Context) error {
+	request := model.PayrollTemplate{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	template, err := h.Service.SaveTemplate(c.Param("name"), &request)
+	if err != nil {
+		return payrollError(c, "Gagal menyimpan template payroll", err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate: DELETE /payroll/templates/:name
+func (h *PayrollHandler) DeleteTemplate(c echo.Context) error {
+	if err := h.Service.Repo.DeleteTemplate(c.Param("name")); err != nil {
+		return payrollError(c, "Gagal menghapus template payroll", err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": "Template payroll dihapus"})
+}
+
+// GetEmployeeIDs: GET /payroll/templates/:name/employee-ids
+func (h *PayrollHandler) GetEmployeeIDs(c echo.Context) error {
+	name := c.Param("name")
+	if _, err := h.Service.Repo.GetTemplate(name); err != nil {
+		return payrollError(c, "Gagal mengambil ID payroll", err)
+	}
+	ids, err := h.Service.Repo.EmployeeIDs(name)
+	if err != nil {
+		return payrollError(c, "Gagal mengambil ID payroll", err)
+	}
+	return c.JSON(http.StatusOK, ids)
+}
+
+// SaveEmployeeIDs: PUT /payroll/templates/:name/employee-ids
+// [{"nik": "123", "external_id": "EMP-0042"}], external_id kosong menghapus pemetaan
+func (h *PayrollHandler) SaveEmployeeIDs(c echo.Context) error {
+	request := []model.PayrollEmployeeID{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := h.Service.SaveEmployeeIDs(c.Param("name"), request); err != nil {
+		return payrollError(c, "Gagal menyimpan ID payroll", err)
+	}
+	return c.JSON(http.StatusOK, echo.Map{"message": fmt.Sprintf("%d pemetaan ID payroll disimpan", len(request))})
+}
+
+// GetTotals: GET /payroll/totals?month=2025-12 atau ?from=2025-11-21&to=2025-12-20
+func (h *PayrollHandler) GetTotals(c echo.Context) error {
+	request := model.PayrollPeriodRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	from, to, err := h.Service.Period(request)
+	if err != nil {
+		return payrollError(c, "Gagal menghitung total payroll", err)
+	}
+
+	totals, err := h.Service.Totals(from, to, request.OrgFilter)
+	if err != nil {
+		return payrollError(c, "Gagal menghitung total payroll", err)
+	}
+	return c.JSON(http.StatusOK, totals)
+}
+
+// Export: GET /export/payroll?template=default&month=2025-12&department_id=1
+// File dibuat lengkap sebelum dikirim, sehingga karyawan tanpa ID payroll
+// masih bisa dilaporkan sebagai 409.
+func (h *PayrollHandler) Export(c echo.Context) error {
+	request := model.PayrollPeriodRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "Parameter tidak valid"})
+	}
+	if request.Template == "" {
+		request.Template = "default"
+	}
+	from, to, err := h.Service.Period(request)
+	if err != nil {
+		return payrollError(c, "Gagal membuat export payroll", err)
+	}
+
+	template, rows, err := h.Service.Export(request.Template, from, to, request.OrgFilter)
+	if err != nil {
+		return payrollError(c, "Gagal membuat export payroll", err)
+	}
+
+	filename := fmt.Sprintf("payroll-%s-%s_%s.csv", template.Name, sitetime.FormatDate(from), sitetime.FormatDate(to))
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	res.WriteHeader(http.StatusOK)
+	if err := payroll.Write(res, template, rows); err != nil {
+		log.Printf("Export %s gagal: %v", filename, err)
+	}
+	return nil
+}
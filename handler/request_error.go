@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// requestError: error pengajuan lembur, cuti dan koreksi. Kesalahan validasi
+// -> 400, bukan wewenang -> 403, tidak ditemukan -> 404, sudah diputuskan
+// -> 409, selain itu 500
+func requestError(c echo.Context, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidOvertime), errors.Is(err, service.ErrInvalidLeave),
+		errors.Is(err, service.ErrInvalidCorrection):
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	case errors.Is(err, service.ErrNotHR), errors.Is(err, service.ErrNotApprover):
+		return c.JSON(http.StatusForbidden, echo.Map{"message": err.Error()})
+	case errors.Is(err, repository.ErrRequestNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"message": err.Error()})
+	case errors.Is(err, repository.ErrRequestNotPending):
+		return c.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, echo.Map{
+		"message": message,
+		"error":   err.Error(),
+	})
+}
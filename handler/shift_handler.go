@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ShiftHandler struct {
+	Service *service.ShiftService
+}
+
+func NewShiftHandler(service *service.ShiftService) *ShiftHandler {
+	return &ShiftHandler{Service: service}
+}
+
+// scheduleError: ErrInvalidSchedule -> 400, selain itu 500
+func scheduleError(c echo.Context, message string, err error) error {
+	if errors.Is(err, service.ErrInvalidSchedule) {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": err.Error(),
+		})
+	}
+	return c.JSON(http.StatusInternalServerError, echo.Map{
+		"message": message,
+		"error":   err.Error(),
+	})
+}
+
+func (h *ShiftHandler) CreateShift(c echo.Context) error {
+	request := model.CreateShiftRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	shift, err := h.Service.CreateShift(&request)
+	if err != nil {
+		return scheduleError(c, "Gagal menyimpan shift", err)
+	}
+	return c.JSON(http.StatusCreated, shift)
+}
+
+func (h *ShiftHandler) GetShifts(c echo.Context) error {
+	shifts, err := h.Service.Repo.GetShifts()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil data shift",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, shifts)
+}
+
+func (h *ShiftHandler) CreatePattern(c echo.Context) error {
+	request := model.CreatePatternRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	pattern, err := h.Service.CreatePattern(&request)
+	if err != nil {
+		return scheduleError(c, "Gagal menyimpan pola jadwal", err)
+	}
+	return c.JSON(http.StatusCreated, pattern)
+}
+
+func (h *ShiftHandler) GetPatterns(c echo.Context) error {
+	patterns, err := h.Service.Repo.GetPatterns()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil pola jadwal",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, patterns)
+}
+
+func (h *ShiftHandler) AssignSchedule(c echo.Context) error {
+	request := model.AssignScheduleRequest{}
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Format data tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	assignment, err := h.Service.AssignSchedule(&request)
+	if err != nil {
+		return scheduleError(c, "Gagal menyimpan jadwal", err)
+	}
+	return c.JSON(http.StatusCreated, assignment)
+}
+
+// GetPlannedShift: GET /users/:nik/shift?date=2025-12-14
+func (h *ShiftHandler) GetPlannedShift(c echo.Context) error {
+	date, err := sitetime.ParseDate(c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter 'date' wajib diisi dengan format YYYY-MM-DD",
+		})
+	}
+
+	planned, err := h.Service.PlannedShift(c.Param("nik"), date)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal menghitung jadwal shift",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, planned)
+}
@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"Steril-App/internal/repository"
 	"Steril-App/internal/service"
 	"Steril-App/model"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -30,6 +32,11 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 	err = h.Service.CreateUser(req)
 	if err != nil {
 
+		if errors.Is(err, service.ErrInvalidEmployment) {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": err.Error(),
+			})
+		}
 		if errors.Is(err, service.ErrUserAlreadyExists) {
 			log.Printf("Handler: Konflik Bisnis: %v", err)
 			return c.JSON(http.StatusConflict, echo.Map{
@@ -74,10 +81,73 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 }
 
 func (h *UserHandler) GetAllUser(c echo.Context) error {
+	filter := model.OrgFilter{}
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Parameter filter tidak valid",
+		})
+	}
 
-	rows, err := h.Service.GetAllUser()
+	rows, err := h.Service.GetAllUser(filter)
 	if err != nil {
 		return c.JSON(http.StatusBadGateway, "error")
 	}
 	return c.JSON(http.StatusOK, rows)
 }
+
+// UpdateEmployment: PUT /users/:nik/employment
+func (h *UserHandler) UpdateEmployment(c echo.Context) error {
+	nik := c.Param("nik")
+	req := model.EmploymentData{}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{
+			"message": "Request body tidak sesuai",
+		})
+	}
+
+	err := h.Service.UpdateEmployment(nik, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidEmployment) {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": err.Error(),
+			})
+		}
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return c.JSON(http.StatusNotFound, echo.Map{
+				"message": "User dengan NIK " + nik + " tidak ditemukan",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal memperbarui data kepegawaian",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "Data kepegawaian berhasil diperbarui",
+		"nik":     nik,
+	})
+}
+
+// GetExpiringContracts: GET /users/contracts/expiring?days=30
+func (h *UserHandler) GetExpiringContracts(c echo.Context) error {
+	days := 30
+	if v := c.QueryParam("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return c.JSON(http.StatusBadRequest, echo.Map{
+				"message": "Parameter 'days' tidak valid",
+			})
+		}
+		days = n
+	}
+
+	rows, err := h.Service.GetExpiringContracts(days)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{
+			"message": "Gagal mengambil data kontrak",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(http.StatusOK, rows)
+}
@@ -0,0 +1,110 @@
+// Package blobstore menyimpan file biner (lampiran catatan) di luar database.
+//
+// Store adalah antarmuka penyimpanan; LocalStore menyimpan di disk lokal.
+// Implementasi lain (S3, MinIO, ...) cukup memenuhi Store.
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("file tidak ditemukan")
+
+type Store interface {
+	// Put menyimpan isi r dengan kunci key; key yang sudah ada ditimpa
+	Put(key string, r io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	// Delete menghapus key; key yang tidak ada bukan kesalahan
+	Delete(key string) error
+}
+
+// LocalStore: setiap key menjadi file di bawah Root
+type LocalStore struct {
+	Root string
+}
+
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori penyimpanan %s: %w", root, err)
+	}
+	return &LocalStore{Root: root}, nil
+}
+
+// FromEnv: LocalStore di NOTE_ATTACHMENT_DIR, default "data/attachments"
+func FromEnv() (Store, error) {
+	root := os.Getenv("NOTE_ATTACHMENT_DIR")
+	if root == "" {
+		root = filepath.Join("data", "attachments")
+	}
+	return NewLocalStore(root)
+}
+
+// path: key hanya boleh berisi segmen relatif tanpa ".." agar tidak keluar dari Root
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("key tidak valid: %q", key)
+	}
+	return filepath.Join(s.Root, clean), nil
+}
+
+// Put: ditulis ke file sementara lalu di-rename, sehingga file setengah jadi
+// tidak pernah terlihat dengan nama key
+func (s *LocalStore) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("gagal membuat direktori: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("gagal membuat file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal menulis file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("gagal menulis file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("gagal menyimpan file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *LocalStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("gagal menghapus file: %w", err)
+	}
+	return nil
+}
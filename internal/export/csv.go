@@ -0,0 +1,53 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV: baris langsung di-flush per 500 baris agar response mengalir
+func WriteCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+
+	headers := make([]string, 0, len(report.Columns)+1)
+	if report.GroupLabel != "" {
+		headers = append(headers, report.GroupLabel)
+	}
+	for _, col := range report.Columns {
+		headers = append(headers, col.Header)
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	count := 0
+	err := report.Rows(func(row Row) error {
+		record := make([]string, 0, len(headers))
+		if report.GroupLabel != "" {
+			record = append(record, row.Group)
+		}
+		for i, value := range row.Values {
+			kind := KindText
+			if i < len(report.Columns) {
+				kind = report.Columns[i].Kind
+			}
+			record = append(record, FormatValue(value, kind))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		count++
+		if count%500 == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
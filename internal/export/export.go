@@ -0,0 +1,129 @@
+// Package export menulis laporan absensi ke CSV, XLSX dan PDF.
+//
+// Baris laporan dikirim lewat RowSource satu per satu sehingga data besar
+// (misal log scan satu bulan) bisa langsung dialirkan dari cursor database
+// ke response tanpa ditampung seluruhnya di memori.
+package export
+
+import (
+	"Steril-App/internal/sitetime"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Jenis kolom menentukan format angka/tanggal di setiap writer
+const (
+	KindText = iota
+	KindInt
+	KindNumber
+	KindDate
+	KindTime
+	KindDateTime
+)
+
+type Column struct {
+	Header string
+	Width  float64 // Lebar kolom dalam satuan karakter Excel
+	Kind   int
+}
+
+// Row: satu baris laporan. Group dipakai sebagai nama sheet (XLSX),
+// judul bagian (PDF) atau kolom pertama (CSV).
+type Row struct {
+	Group  string
+	Values []interface{}
+}
+
+// RowSource memanggil emit untuk setiap baris. Baris dengan Group yang sama
+// harus berurutan (sudah di-ORDER BY group).
+type RowSource func(emit func(Row) error) error
+
+type Report struct {
+	Title      string
+	Subtitle   string // Contoh: periode laporan
+	GroupLabel string // Header kolom Group pada CSV, contoh "Departemen"
+	Columns    []Column
+	Rows       RowSource
+}
+
+// Company: identitas perusahaan untuk kop laporan PDF
+type Company struct {
+	Name    string
+	Address string
+}
+
+func CompanyFromEnv() Company {
+	return Company{
+		Name:    os.Getenv("COMPANY_NAME"),
+		Address: os.Getenv("COMPANY_ADDRESS"),
+	}
+}
+
+// Write: menulis laporan sesuai format ke w
+func Write(w io.Writer, format string, report Report, company Company) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, report)
+	case FormatXLSX:
+		return WriteXLSX(w, report)
+	case FormatPDF:
+		return WritePDF(w, report, company)
+	default:
+		return fmt.Errorf("format export '%s' tidak didukung", format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func IsSupported(format string) bool {
+	return format == FormatCSV || format == FormatXLSX || format == FormatPDF
+}
+
+// FormatValue: representasi teks sebuah nilai, dipakai CSV dan PDF
+func FormatValue(value interface{}, kind int) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		v = v.In(sitetime.Location())
+		switch kind {
+		case KindDate:
+			return v.Format("2006-01-02")
+		case KindTime:
+			return v.Format("15:04")
+		default:
+			return v.Format("2006-01-02 15:04:05")
+		}
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return FormatValue(*v, kind)
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"Steril-App/internal/sitetime"
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfRowHeight = 6.0
+	pdfFontSize  = 8.0
+)
+
+// WritePDF: laporan siap cetak (A4 landscape) dengan kop perusahaan,
+// judul bagian per Group dan blok tanda tangan di halaman terakhir.
+// PDF harus dibangun utuh sebelum dikirim, jadi format ini cocok untuk
+// laporan rekap, bukan log mentah berukuran besar.
+func WritePDF(w io.Writer, report Report, company Company) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("{nb}")
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	widths := pdfColumnWidths(report.Columns, pageWidth-left-right)
+
+	printHeaderRow := func() {
+		pdf.SetFont("Helvetica", "B", pdfFontSize)
+		pdf.SetFillColor(48, 84, 150)
+		pdf.SetTextColor(255, 255, 255)
+		for i, col := range report.Columns {
+			pdf.CellFormat(widths[i], pdfRowHeight, tr(col.Header), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+	}
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.CellFormat(0, 6, tr(company.Name), "", 1, "L", false, 0, "")
+		if company.Address != "" {
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.CellFormat(0, 5, tr(company.Address), "", 1, "L", false, 0, "")
+		}
+		y := pdf.GetY() + 1
+		pdf.Line(left, y, pageWidth-right, y)
+		pdf.Ln(3)
+
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 6, tr(report.Title), "", 1, "C", false, 0, "")
+		if report.Subtitle != "" {
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.CellFormat(0, 5, tr(report.Subtitle), "", 1, "C", false, 0, "")
+		}
+		pdf.Ln(2)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 7)
+		pdf.CellFormat(0, 5, tr("Dicetak "+sitetime.Now().Format("2006-01-02 15:04")), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, tr("Halaman ")+strconv.Itoa(pdf.PageNo())+" / {nb}", "", 0, "R", false, 0, "")
+	})
+
+	pdf.AddPage()
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+
+	currentGroup := ""
+	started := false
+	err := report.Rows(func(row Row) error {
+		if !started || row.Group != currentGroup {
+			started = true
+			currentGroup = row.Group
+			if row.Group != "" {
+				pdf.Ln(2)
+				pdf.SetFont("Helvetica", "B", 9)
+				pdf.CellFormat(0, pdfRowHeight, tr(report.GroupLabel+": "+row.Group), "", 1, "L", false, 0, "")
+			}
+			printHeaderRow()
+		}
+
+		// Ulangi header tabel ketika pindah halaman
+		if pdf.GetY()+pdfRowHeight > pageHeight-bottom {
+			pdf.AddPage()
+			printHeaderRow()
+		}
+
+		for i := range report.Columns {
+			var value interface{}
+			if i < len(row.Values) {
+				value = row.Values[i]
+			}
+			align := "L"
+			if k := report.Columns[i].Kind; k == KindInt || k == KindNumber {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], pdfRowHeight, tr(FormatValue(value, report.Columns[i].Kind)), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+		return pdf.Error()
+	})
+	if err != nil {
+		return err
+	}
+	if !started {
+		printHeaderRow()
+	}
+
+	writeSignatureBlock(pdf, tr, pageWidth-left-right)
+	return pdf.Output(w)
+}
+
+func writeSignatureBlock(pdf *fpdf.Fpdf, tr func(string) string, width float64) {
+	const blockHeight = 35.0
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	if pdf.GetY()+blockHeight > pageHeight-bottom {
+		pdf.AddPage()
+	}
+
+	labels := []string{"Dibuat oleh,", "Diperiksa oleh,", "Disetujui oleh,"}
+	colWidth := width / float64(len(labels))
+
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "", 9)
+	for _, label := range labels {
+		pdf.CellFormat(colWidth, 5, tr(label), "", 0, "C", false, 0, "")
+	}
+	pdf.Ln(20)
+	for range labels {
+		pdf.CellFormat(colWidth, 5, "(.................................)", "", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+// pdfColumnWidths: lebar kolom proporsional terhadap Column.Width
+func pdfColumnWidths(columns []Column, total float64) []float64 {
+	sum := 0.0
+	for _, col := range columns {
+		if col.Width == 0 {
+			sum += 14
+		} else {
+			sum += col.Width
+		}
+	}
+
+	widths := make([]float64, len(columns))
+	for i, col := range columns {
+		w := col.Width
+		if w == 0 {
+			w = 14
+		}
+		widths[i] = total * w / sum
+	}
+	return widths
+}
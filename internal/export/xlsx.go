@@ -0,0 +1,220 @@
+package export
+
+import (
+	"Steril-App/internal/sitetime"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxStyles: ID style per jenis kolom, dibuat sekali per file
+type xlsxStyles struct {
+	title  int
+	header int
+	kinds  map[int]int
+}
+
+// WriteXLSX: satu sheet per Group. Tiap sheet ditulis dengan StreamWriter,
+// sehingga baris disimpan ke file sementara, bukan ke memori.
+func WriteXLSX(w io.Writer, report Report) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return err
+	}
+
+	var (
+		sw        *excelize.StreamWriter
+		rowNum    int
+		usedNames = make(map[string]bool)
+		sheets    int
+	)
+
+	startSheet := func(group string) error {
+		name := sheetName(group, usedNames)
+		if sheets == 0 {
+			if err := f.SetSheetName("Sheet1", name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+		sheets++
+
+		sw, err = f.NewStreamWriter(name)
+		if err != nil {
+			return err
+		}
+		for i, col := range report.Columns {
+			width := col.Width
+			if width == 0 {
+				width = 14
+			}
+			if err := sw.SetColWidth(i+1, i+1, width); err != nil {
+				return err
+			}
+		}
+		if err := sw.SetPanes(&excelize.Panes{
+			Freeze: true, YSplit: 4, TopLeftCell: "A5", ActivePane: "bottomLeft",
+		}); err != nil {
+			return err
+		}
+
+		title := []interface{}{excelize.Cell{StyleID: styles.title, Value: report.Title}}
+		if err := sw.SetRow("A1", title); err != nil {
+			return err
+		}
+		subtitle := report.Subtitle
+		if group != "" {
+			subtitle = strings.TrimSpace(subtitle + " - " + group)
+		}
+		if err := sw.SetRow("A2", []interface{}{subtitle}); err != nil {
+			return err
+		}
+
+		headers := make([]interface{}, len(report.Columns))
+		for i, col := range report.Columns {
+			headers[i] = excelize.Cell{StyleID: styles.header, Value: col.Header}
+		}
+		if err := sw.SetRow("A4", headers); err != nil {
+			return err
+		}
+		rowNum = 5
+		return nil
+	}
+
+	currentGroup := ""
+	err = report.Rows(func(row Row) error {
+		if sw == nil || row.Group != currentGroup {
+			if sw != nil {
+				if err := sw.Flush(); err != nil {
+					return err
+				}
+			}
+			currentGroup = row.Group
+			if err := startSheet(row.Group); err != nil {
+				return err
+			}
+		}
+
+		cells := make([]interface{}, len(row.Values))
+		for i, value := range row.Values {
+			kind := KindText
+			if i < len(report.Columns) {
+				kind = report.Columns[i].Kind
+			}
+			cells[i] = xlsxCell(value, kind, styles)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+		rowNum++
+		return sw.SetRow(cell, cells)
+	})
+	if err != nil {
+		return err
+	}
+
+	if sw == nil {
+		// Tidak ada data: tetap kirim satu sheet berisi judul dan header
+		if err := startSheet(""); err != nil {
+			return err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	styles := xlsxStyles{kinds: make(map[int]int)}
+	var err error
+
+	styles.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return styles, err
+	}
+
+	border := []excelize.Border{
+		{Type: "left", Color: "999999", Style: 1},
+		{Type: "right", Color: "999999", Style: 1},
+		{Type: "top", Color: "999999", Style: 1},
+		{Type: "bottom", Color: "999999", Style: 1},
+	}
+	styles.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"305496"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+		Border:    border,
+	})
+	if err != nil {
+		return styles, err
+	}
+
+	formats := map[int]string{
+		KindText:     "@",
+		KindInt:      "0",
+		KindNumber:   "0.00",
+		KindDate:     "yyyy-mm-dd",
+		KindTime:     "hh:mm",
+		KindDateTime: "yyyy-mm-dd hh:mm:ss",
+	}
+	for kind, numFmt := range formats {
+		format := numFmt
+		id, err := f.NewStyle(&excelize.Style{CustomNumFmt: &format, Border: border})
+		if err != nil {
+			return styles, err
+		}
+		styles.kinds[kind] = id
+	}
+	return styles, nil
+}
+
+func xlsxCell(value interface{}, kind int, styles xlsxStyles) interface{} {
+	switch v := value.(type) {
+	case *time.Time:
+		if v == nil {
+			value = nil
+		} else {
+			value = *v
+		}
+	}
+	if t, ok := value.(time.Time); ok {
+		if t.IsZero() {
+			value = nil
+		} else {
+			// Excel tidak mengenal zona waktu: tulis jam lokal apa adanya
+			t = t.In(sitetime.Location())
+			value = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+		}
+	}
+	return excelize.Cell{StyleID: styles.kinds[kind], Value: value}
+}
+
+// sheetName: nama sheet Excel maksimal 31 karakter, unik, tanpa karakter terlarang
+func sheetName(group string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(group))
+	if name == "" {
+		name = "Data"
+	}
+	if runes := []rune(name); len(runes) > 28 {
+		name = string(runes[:28])
+	}
+
+	candidate := name
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		candidate = name + " " + strconv.Itoa(i)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
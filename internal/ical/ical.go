@@ -0,0 +1,140 @@
+// Package ical membaca event dari file iCalendar (.ics, RFC 5545).
+//
+// Hanya bagian yang dibutuhkan kalender hari libur yang didukung: VEVENT
+// dengan DTSTART, DTEND dan SUMMARY. RRULE tidak diekspansi; kalender libur
+// nasional umumnya sudah mencantumkan setiap tanggal sebagai event sendiri.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time // Tanggal pertama (00:00 UTC)
+	End     time.Time // Tanggal terakhir, inklusif
+}
+
+// Dates: setiap tanggal yang dicakup event
+func (e Event) Dates() []time.Time {
+	dates := []time.Time{}
+	for d := e.Start; !d.After(e.End); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// Parse: membaca semua VEVENT dari r
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	var current *Event
+	var endValue string
+	for i, line := range lines {
+		name, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &Event{}
+			endValue = ""
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				return nil, fmt.Errorf("baris %d: END:VEVENT tanpa BEGIN", i+1)
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("baris %d: event '%s' tidak memiliki DTSTART", i+1, current.Summary)
+			}
+			current.End = current.Start
+			if endValue != "" {
+				end, exclusive, err := parseDate(endValue)
+				if err != nil {
+					return nil, fmt.Errorf("baris %d: %w", i+1, err)
+				}
+				// DTEND bertipe tanggal (atau tepat tengah malam) bersifat eksklusif
+				if exclusive {
+					end = end.AddDate(0, 0, -1)
+				}
+				if !end.Before(current.Start) {
+					current.End = end
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescape(value)
+		case name == "DTSTART":
+			start, _, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("baris %d: %w", i+1, err)
+			}
+			current.Start = start
+		case name == "DTEND":
+			endValue = value
+		}
+	}
+	return events, nil
+}
+
+// unfold: baris yang diawali spasi/tab adalah lanjutan baris sebelumnya
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gagal membaca file ics: %w", err)
+	}
+	return lines, nil
+}
+
+// splitProperty: "DTSTART;VALUE=DATE:20250101" -> ("DTSTART", "20250101").
+// Parameter (VALUE, TZID, ...) diabaikan.
+func splitProperty(line string) (string, string, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+	name, _, _ := strings.Cut(head, ";")
+	return strings.ToUpper(name), value, true
+}
+
+// parseDate: menerima "20250101" atau "20250101T080000[Z]". Jam diabaikan,
+// exclusive bernilai true jika nilai berupa tanggal saja atau tepat 00:00.
+func parseDate(value string) (time.Time, bool, error) {
+	if len(value) < 8 {
+		return time.Time{}, false, fmt.Errorf("tanggal '%s' tidak valid", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("tanggal '%s' tidak valid", value)
+	}
+	clock := strings.TrimSuffix(value[8:], "Z")
+	return date, clock == "" || clock == "T000000", nil
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,154 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func calendar(events ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(events, "") + "END:VCALENDAR\r\n"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Event
+	}{
+		{
+			name: "VALUE=DATE satu hari, DTEND eksklusif",
+			input: calendar("BEGIN:VEVENT\r\nUID:a@id\r\nDTSTART;VALUE=DATE:20251225\r\nDTEND;VALUE=DATE:20251226\r\n" +
+				"SUMMARY:Hari Raya Natal\r\nEND:VEVENT\r\n"),
+			want: []Event{{UID: "a@id", Summary: "Hari Raya Natal", Start: day(2025, 12, 25), End: day(2025, 12, 25)}},
+		},
+		{
+			name:  "tanpa DTEND = satu hari",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250101\r\nSUMMARY:Tahun Baru\r\nEND:VEVENT\r\n"),
+			want:  []Event{{Summary: "Tahun Baru", Start: day(2025, 1, 1), End: day(2025, 1, 1)}},
+		},
+		{
+			name: "DTEND banyak hari melewati pergantian bulan",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250330\r\nDTEND;VALUE=DATE:20250403\r\n" +
+				"SUMMARY:Cuti Bersama Idul Fitri\r\nEND:VEVENT\r\n"),
+			want: []Event{{Summary: "Cuti Bersama Idul Fitri", Start: day(2025, 3, 30), End: day(2025, 4, 2)}},
+		},
+		{
+			name: "DATE-TIME, DTEND di tengah hari inklusif",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART:20250817T080000\r\nDTEND:20250817T120000\r\n" +
+				"SUMMARY:Upacara\r\nEND:VEVENT\r\n"),
+			want: []Event{{Summary: "Upacara", Start: day(2025, 8, 17), End: day(2025, 8, 17)}},
+		},
+		{
+			name: "DATE-TIME UTC, DTEND tengah malam eksklusif",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART;TZID=Asia/Jakarta:20250501T000000\r\nDTEND:20250503T000000Z\r\n" +
+				"SUMMARY:Dua hari\r\nEND:VEVENT\r\n"),
+			want: []Event{{Summary: "Dua hari", Start: day(2025, 5, 1), End: day(2025, 5, 2)}},
+		},
+		{
+			name: "DATE-TIME banyak hari, DTEND bukan tengah malam",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART:20250501T090000Z\r\nDTEND:20250503T170000Z\r\n" +
+				"SUMMARY:Tiga hari\r\nEND:VEVENT\r\n"),
+			want: []Event{{Summary: "Tiga hari", Start: day(2025, 5, 1), End: day(2025, 5, 3)}},
+		},
+		{
+			name: "DTEND sebelum DTSTART diabaikan",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250105\r\nDTEND;VALUE=DATE:20250101\r\n" +
+				"SUMMARY:Salah\r\nEND:VEVENT\r\n"),
+			want: []Event{{Summary: "Salah", Start: day(2025, 1, 5), End: day(2025, 1, 5)}},
+		},
+		{
+			name: "baris terlipat (spasi dan tab) dan karakter escape",
+			input: calendar("BEGIN:VEVENT\r\nUID:1234567890@calendar.exa\r\n mple.com\r\nDTSTART;VALUE=DATE:20250529\r\n" +
+				"SUMMARY:Kenaikan Yesus Kristus\\, libur\r\n\t nasional\\; cuti\\nbersama\r\nEND:VEVENT\r\n"),
+			want: []Event{{
+				UID:     "1234567890@calendar.example.com",
+				Summary: "Kenaikan Yesus Kristus, libur nasional; cuti bersama",
+				Start:   day(2025, 5, 29), End: day(2025, 5, 29),
+			}},
+		},
+		{
+			name:  "akhir baris LF saja",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20250101\nSUMMARY:LF\nEND:VEVENT\nEND:VCALENDAR\n",
+			want:  []Event{{Summary: "LF", Start: day(2025, 1, 1), End: day(2025, 1, 1)}},
+		},
+		{
+			// RRULE tidak diekspansi: hanya kemunculan pertama yang diambil
+			name: "RRULE tidak diekspansi",
+			input: calendar("BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250817\r\nRRULE:FREQ=YEARLY\r\n" +
+				"SUMMARY:HUT RI\r\nEND:VEVENT\r\n"),
+			want: []Event{{Summary: "HUT RI", Start: day(2025, 8, 17), End: day(2025, 8, 17)}},
+		},
+		{
+			name: "nama properti huruf kecil, komponen lain dilewati",
+			input: calendar("BEGIN:VTIMEZONE\r\nTZID:Asia/Jakarta\r\nDTSTART:19700101T000000\r\nEND:VTIMEZONE\r\n" +
+				"BEGIN:VEVENT\r\ndtstart;value=DATE:20250101\r\nsummary:a\r\nEND:VEVENT\r\n" +
+				"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250102\r\nSUMMARY:b\r\nEND:VEVENT\r\n"),
+			want: []Event{
+				{Summary: "a", Start: day(2025, 1, 1), End: day(2025, 1, 1)},
+				{Summary: "b", Start: day(2025, 1, 2), End: day(2025, 1, 2)},
+			},
+		},
+		{
+			name:  "tanpa event",
+			input: calendar(),
+			want:  []Event{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse = %d event, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"tanpa DTSTART", calendar("BEGIN:VEVENT\r\nSUMMARY:x\r\nEND:VEVENT\r\n"), "DTSTART"},
+		{"END tanpa BEGIN", calendar("END:VEVENT\r\n"), "tanpa BEGIN"},
+		{"DTSTART tidak valid", calendar("BEGIN:VEVENT\r\nDTSTART:2025-01-01\r\nEND:VEVENT\r\n"), "tidak valid"},
+		{"DTSTART terlalu pendek", calendar("BEGIN:VEVENT\r\nDTSTART:2025\r\nEND:VEVENT\r\n"), "tidak valid"},
+		{"DTEND tidak valid", calendar("BEGIN:VEVENT\r\nDTSTART:20250101\r\nDTEND:20251301\r\nEND:VEVENT\r\n"), "tidak valid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Parse error = %v, want mengandung %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventDates(t *testing.T) {
+	event := Event{Start: day(2025, 12, 30), End: day(2026, 1, 2)}
+	want := []time.Time{day(2025, 12, 30), day(2025, 12, 31), day(2026, 1, 1), day(2026, 1, 2)}
+	got := event.Dates()
+	if len(got) != len(want) {
+		t.Fatalf("Dates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Dates[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
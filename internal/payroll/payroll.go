@@ -0,0 +1,252 @@
+// Package payroll menulis total absensi per periode ke file import sistem
+// payroll menurut template (urutan kolom, header, format angka dan tanggal,
+// serta ID karyawan).
+package payroll
+
+import (
+	"Steril-App/model"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Jenis nilai field menentukan format di file
+const (
+	kindText = iota
+	kindInt
+	kindHours
+	kindDate
+)
+
+// Row: total satu karyawan beserta ID yang ditulis di kolom employee_id
+type Row struct {
+	EmployeeID string
+	Totals     model.PayrollTotals
+}
+
+type field struct {
+	kind  int
+	value func(Row) interface{}
+}
+
+func minutesToHours(minutes int) float64 {
+	return float64(minutes) / 60
+}
+
+// fields: katalog field yang bisa dipakai kolom template
+var fields = map[string]field{
+	"nik":                     {kindText, func(r Row) interface{} { return r.Totals.NIK }},
+	"employee_id":             {kindText, func(r Row) interface{} { return r.EmployeeID }},
+	"full_name":               {kindText, func(r Row) interface{} { return r.Totals.FullName }},
+	"department":              {kindText, func(r Row) interface{} { return r.Totals.DepartmentName }},
+	"period_from":             {kindDate, func(r Row) interface{} { return r.Totals.From }},
+	"period_to":               {kindDate, func(r Row) interface{} { return r.Totals.To }},
+	"workdays":                {kindInt, func(r Row) interface{} { return r.Totals.Workdays }},
+	"days_present":            {kindInt, func(r Row) interface{} { return r.Totals.DaysPresent }},
+	"late_count":              {kindInt, func(r Row) interface{} { return r.Totals.LateCount }},
+	"late_minutes":            {kindInt, func(r Row) interface{} { return r.Totals.LateMinutes }},
+	"early_leave_minutes":     {kindInt, func(r Row) interface{} { return r.Totals.EarlyLeaveMinutes }},
+	"incomplete_days":         {kindInt, func(r Row) interface{} { return r.Totals.IncompleteDays }},
+	"absences":                {kindInt, func(r Row) interface{} { return r.Totals.Absences }},
+	"leave_days":              {kindInt, func(r Row) interface{} { return r.Totals.LeaveDays }},
+	"worked_hours":            {kindHours, func(r Row) interface{} { return r.Totals.WorkedHours }},
+	"overtime_hours":          {kindHours, func(r Row) interface{} { return r.Totals.OvertimeHours }},
+	"overtime_x1_5_hours":     {kindHours, func(r Row) interface{} { return minutesToHours(r.Totals.OvertimeTiers.X1_5) }},
+	"overtime_x2_hours":       {kindHours, func(r Row) interface{} { return minutesToHours(r.Totals.OvertimeTiers.X2) }},
+	"overtime_x3_hours":       {kindHours, func(r Row) interface{} { return minutesToHours(r.Totals.OvertimeTiers.X3) }},
+	"overtime_x4_hours":       {kindHours, func(r Row) interface{} { return minutesToHours(r.Totals.OvertimeTiers.X4) }},
+	"overtime_weighted_hours": {kindHours, func(r Row) interface{} { return r.Totals.OvertimeWeightedHours }},
+}
+
+const (
+	leavePrefix = "leave:"
+	constField  = "const"
+)
+
+// Fields: nama field yang tersedia, untuk dokumentasi/validasi di klien
+func Fields() []string {
+	names := make([]string, 0, len(fields)+2)
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append(names, leavePrefix+"<kode>", constField)
+}
+
+func lookup(column model.PayrollColumn) (field, bool) {
+	switch {
+	case column.Field == constField:
+		value := column.Value
+		return field{kindText, func(Row) interface{} { return value }}, true
+	case strings.HasPrefix(column.Field, leavePrefix):
+		code := strings.TrimPrefix(column.Field, leavePrefix)
+		return field{kindInt, func(r Row) interface{} { return r.Totals.LeaveByType[code] }}, code != ""
+	}
+	f, ok := fields[column.Field]
+	return f, ok
+}
+
+var templateName = regexp.MustCompile(`^[a-z0-9_-]{1,50}$`)
+
+// Normalize: mengisi default dan memvalidasi template
+func Normalize(t *model.PayrollTemplate) error {
+	if !templateName.MatchString(t.Name) {
+		return fmt.Errorf("nama template hanya boleh huruf kecil, angka, '-' dan '_' (maks 50)")
+	}
+	if t.Delimiter == "" {
+		t.Delimiter = ","
+	}
+	if t.Delimiter == `\t` {
+		t.Delimiter = "\t"
+	}
+	delimiter, size := utf8.DecodeRuneInString(t.Delimiter)
+	if size != len(t.Delimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+		return fmt.Errorf("delimiter harus satu karakter selain tanda kutip dan baris baru")
+	}
+	if t.DecimalSeparator == "" {
+		t.DecimalSeparator = "."
+	}
+	if t.DecimalSeparator != "." && t.DecimalSeparator != "," {
+		return fmt.Errorf("decimal_separator harus '.' atau ','")
+	}
+	if t.Decimals == nil {
+		decimals := 2
+		t.Decimals = &decimals
+	}
+	if *t.Decimals < 0 || *t.Decimals > 6 {
+		return fmt.Errorf("decimals harus 0-6")
+	}
+	if t.DateFormat == "" {
+		t.DateFormat = "YYYY-MM-DD"
+	}
+	if _, err := dateLayout(t.DateFormat); err != nil {
+		return err
+	}
+	if t.Identifier == "" {
+		t.Identifier = model.IdentifierNIK
+	}
+	if t.Identifier != model.IdentifierNIK && t.Identifier != model.IdentifierMapped {
+		return fmt.Errorf("identifier harus 'nik' atau 'mapped'")
+	}
+	if t.Unmapped == "" {
+		t.Unmapped = model.UnmappedError
+	}
+	if t.Unmapped != model.UnmappedError && t.Unmapped != model.UnmappedSkip && t.Unmapped != model.UnmappedNIK {
+		return fmt.Errorf("unmapped harus 'error', 'skip' atau 'nik'")
+	}
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("template minimal berisi satu kolom")
+	}
+	for i, column := range t.Columns {
+		if _, ok := lookup(column); !ok {
+			return fmt.Errorf("kolom %d: field '%s' tidak dikenal", i+1, column.Field)
+		}
+		if column.Decimals != nil && (*column.Decimals < 0 || *column.Decimals > 6) {
+			return fmt.Errorf("kolom %d: decimals harus 0-6", i+1)
+		}
+	}
+	return nil
+}
+
+// dateTokens: token date_format ke layout Go, token terpanjang dicoba lebih dulu
+var dateTokens = []struct{ token, layout string }{
+	{"YYYY", "2006"},
+	{"YY", "06"},
+	{"MM", "01"},
+	{"DD", "02"},
+}
+
+// dateSeparators: karakter di luar token yang disalin apa adanya. Huruf, angka
+// dan '_' ditolak karena bisa ditafsirkan sebagai bagian layout Go.
+const dateSeparators = " /-.,:"
+
+// dateLayout: "DD/MM/YYYY" -> "02/01/2006"
+func dateLayout(format string) (string, error) {
+	var layout strings.Builder
+	rest := format
+next:
+	for rest != "" {
+		for _, t := range dateTokens {
+			if strings.HasPrefix(rest, t.token) {
+				layout.WriteString(t.layout)
+				rest = rest[len(t.token):]
+				continue next
+			}
+		}
+		if !strings.ContainsRune(dateSeparators, rune(rest[0])) {
+			return "", fmt.Errorf("date_format hanya boleh berisi token YYYY, YY, MM, DD dan pemisah %q", dateSeparators)
+		}
+		layout.WriteByte(rest[0])
+		rest = rest[1:]
+	}
+	return layout.String(), nil
+}
+
+// Write: menulis rows sesuai template. Template harus sudah di-Normalize.
+func Write(w io.Writer, t model.PayrollTemplate, rows []Row) error {
+	layout, err := dateLayout(t.DateFormat)
+	if err != nil {
+		return err
+	}
+	columns := make([]field, len(t.Columns))
+	headers := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		f, ok := lookup(column)
+		if !ok {
+			return fmt.Errorf("field '%s' tidak dikenal", column.Field)
+		}
+		columns[i] = f
+		headers[i] = column.Header
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma, _ = utf8.DecodeRuneInString(t.Delimiter)
+	if !t.SkipHeader {
+		if err := writer.Write(headers); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, f := range columns {
+			decimals := *t.Decimals
+			if d := t.Columns[i].Decimals; d != nil {
+				decimals = *d
+			}
+			record[i] = formatValue(f.kind, f.value(row), decimals, t.DecimalSeparator, layout)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatValue(kind int, value interface{}, decimals int, separator, layout string) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		scale := math.Pow10(decimals)
+		text := strconv.FormatFloat(math.Round(v*scale)/scale, 'f', decimals, 64)
+		return strings.Replace(text, ".", separator, 1)
+	case string:
+		if kind == kindDate {
+			if date, err := time.Parse("2006-01-02", v); err == nil {
+				return date.Format(layout)
+			}
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
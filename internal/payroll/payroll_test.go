@@ -0,0 +1,205 @@
+package payroll
+
+import (
+	"Steril-App/model"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestDateLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{format: "YYYY-MM-DD", want: "2006-01-02"},
+		{format: "DD/MM/YYYY", want: "02/01/2006"},
+		{format: "DD.MM.YY", want: "02.01.06"},
+		{format: "YYYYMMDD", want: "20060102"},
+		{format: "MM/DD/YYYY, DD:MM", want: "01/02/2006, 02:01"},
+		{format: "YYYYY", wantErr: true},                // Sisa "Y" bukan token
+		{format: "DD MMM YYYY", wantErr: true},          // "MMM" bukan token
+		{format: "DD-Jan-YYYY", wantErr: true},          // Nama bulan layout Go
+		{format: "YYYY-MM-DD 15", wantErr: true},        // Angka = jam di layout Go
+		{format: "YYYY_MM_DD", wantErr: true},           // "_" = padding di layout Go
+		{format: "DD/MM/2006", wantErr: true},           // Angka literal ditolak
+		{format: "dd/mm/yyyy", wantErr: true},           // Token huruf besar saja
+		{format: "YYYY\u00a0MM\u00a0DD", wantErr: true}, // Spasi non-ASCII
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := dateLayout(tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("dateLayout(%q) = %q, want error", tt.format, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("dateLayout(%q) = %q, %v; want %q", tt.format, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDefaults(t *testing.T) {
+	template := model.PayrollTemplate{
+		Name:    "default",
+		Columns: []model.PayrollColumn{{Header: "NIK", Field: "nik"}},
+	}
+	if err := Normalize(&template); err != nil {
+		t.Fatal(err)
+	}
+	if template.Delimiter != "," || template.DecimalSeparator != "." || template.DateFormat != "YYYY-MM-DD" ||
+		template.Identifier != model.IdentifierNIK || template.Unmapped != model.UnmappedError {
+		t.Errorf("default tidak terisi: %+v", template)
+	}
+	if template.Decimals == nil || *template.Decimals != 2 {
+		t.Errorf("Decimals = %v, want 2", template.Decimals)
+	}
+
+	tab := model.PayrollTemplate{Name: "tsv", Delimiter: `\t`, Decimals: intPtr(0), Columns: template.Columns}
+	if err := Normalize(&tab); err != nil {
+		t.Fatal(err)
+	}
+	if tab.Delimiter != "\t" || *tab.Decimals != 0 {
+		t.Errorf("delimiter/decimals = %q/%d, want tab/0", tab.Delimiter, *tab.Decimals)
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	valid := func() model.PayrollTemplate {
+		return model.PayrollTemplate{Name: "sap", Columns: []model.PayrollColumn{{Header: "NIK", Field: "nik"}}}
+	}
+	tests := []struct {
+		name   string
+		modify func(*model.PayrollTemplate)
+		want   string
+	}{
+		{"nama huruf besar", func(t *model.PayrollTemplate) { t.Name = "SAP" }, "nama template"},
+		{"nama kosong", func(t *model.PayrollTemplate) { t.Name = "" }, "nama template"},
+		{"delimiter dua karakter", func(t *model.PayrollTemplate) { t.Delimiter = ";;" }, "delimiter"},
+		{"delimiter kutip", func(t *model.PayrollTemplate) { t.Delimiter = `"` }, "delimiter"},
+		{"delimiter baris baru", func(t *model.PayrollTemplate) { t.Delimiter = "\n" }, "delimiter"},
+		{"pemisah desimal", func(t *model.PayrollTemplate) { t.DecimalSeparator = ";" }, "decimal_separator"},
+		{"decimals negatif", func(t *model.PayrollTemplate) { t.Decimals = intPtr(-1) }, "decimals"},
+		{"decimals terlalu besar", func(t *model.PayrollTemplate) { t.Decimals = intPtr(7) }, "decimals"},
+		{"date_format layout Go", func(t *model.PayrollTemplate) { t.DateFormat = "2006-01-02" }, "date_format"},
+		{"identifier", func(t *model.PayrollTemplate) { t.Identifier = "email" }, "identifier"},
+		{"unmapped", func(t *model.PayrollTemplate) { t.Unmapped = "ignore" }, "unmapped"},
+		{"tanpa kolom", func(t *model.PayrollTemplate) { t.Columns = nil }, "minimal"},
+		{"field tidak dikenal", func(t *model.PayrollTemplate) { t.Columns[0].Field = "salary" }, "tidak dikenal"},
+		{"leave tanpa kode", func(t *model.PayrollTemplate) { t.Columns[0].Field = "leave:" }, "tidak dikenal"},
+		{"decimals kolom", func(t *model.PayrollTemplate) { t.Columns[0].Decimals = intPtr(9) }, "kolom 1: decimals"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := valid()
+			tt.modify(&template)
+			err := Normalize(&template)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Normalize error = %v, want mengandung %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      int
+		value     interface{}
+		decimals  int
+		separator string
+		want      string
+	}{
+		{"int", kindInt, 7, 2, ".", "7"},
+		{"int tidak memakai decimals", kindInt, 1500, 2, ",", "1500"},
+		{"jam dua desimal", kindHours, 1.5, 2, ".", "1.50"},
+		{"pemisah koma", kindHours, 1.5, 2, ",", "1,50"},
+		{"nol desimal dibulatkan ke atas", kindHours, 1.5, 0, ".", "2"},
+		{"nol desimal dibulatkan ke bawah", kindHours, 1.49, 0, ".", "1"},
+		{"setengah ke atas", kindHours, 0.125, 2, ".", "0.13"},
+		{"menit ke jam", kindHours, 50.0 / 60, 2, ",", "0,83"},
+		{"9 menit satu desimal", kindHours, 9.0 / 60, 1, ".", "0.2"},
+		{"enam desimal", kindHours, 1.0 / 3, 6, ".", "0.333333"},
+		{"nol", kindHours, 0.0, 2, ",", "0,00"},
+		{"tanggal", kindDate, "2025-12-01", 2, ".", "01/12/2025"},
+		{"tanggal tidak valid apa adanya", kindDate, "01-12-2025", 2, ".", "01-12-2025"},
+		{"teks", kindText, "2025-12-01", 2, ".", "2025-12-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatValue(tt.kind, tt.value, tt.decimals, tt.separator, "02/01/2006"); got != tt.want {
+				t.Errorf("formatValue = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rows := []Row{
+		{EmployeeID: "E-1001", Totals: model.PayrollTotals{
+			NIK: "1001", FullName: "Budi, S.T.", From: "2025-12-01", To: "2025-12-31",
+			DaysPresent: 20, WorkedHours: 160.5, OvertimeTiers: model.OvertimeTiers{X1_5: 90},
+			LeaveByType: map[string]int{model.LeaveSick: 2},
+		}},
+		{EmployeeID: "E-1002", Totals: model.PayrollTotals{NIK: "1002", FullName: "Sari", From: "2025-12-01", To: "2025-12-31"}},
+	}
+	columns := []model.PayrollColumn{
+		{Header: "ID", Field: "employee_id"},
+		{Header: "Nama", Field: "full_name"},
+		{Header: "Mulai", Field: "period_from"},
+		{Header: "Hadir", Field: "days_present"},
+		{Header: "Jam", Field: "worked_hours"},
+		{Header: "L1.5", Field: "overtime_x1_5_hours", Decimals: intPtr(1)},
+		{Header: "Sakit", Field: "leave:" + model.LeaveSick},
+		{Header: "Perusahaan", Field: "const", Value: "PT A"},
+	}
+
+	tests := []struct {
+		name     string
+		template model.PayrollTemplate
+		want     string
+	}{
+		{
+			name:     "default csv",
+			template: model.PayrollTemplate{Name: "a", Columns: columns},
+			want: "ID,Nama,Mulai,Hadir,Jam,L1.5,Sakit,Perusahaan\n" +
+				"E-1001,\"Budi, S.T.\",2025-12-01,20,160.50,1.5,2,PT A\n" +
+				"E-1002,Sari,2025-12-01,0,0.00,0.0,0,PT A\n",
+		},
+		{
+			name: "titik koma, koma desimal, tanpa header",
+			template: model.PayrollTemplate{Name: "b", Delimiter: ";", DecimalSeparator: ",", DateFormat: "DD.MM.YYYY",
+				SkipHeader: true, Columns: columns},
+			want: "E-1001;Budi, S.T.;01.12.2025;20;160,50;1,5;2;PT A\n" +
+				"E-1002;Sari;01.12.2025;0;0,00;0,0;0;PT A\n",
+		},
+		{
+			name:     "tsv nol desimal",
+			template: model.PayrollTemplate{Name: "c", Delimiter: `\t`, Decimals: intPtr(0), Columns: columns[:5]},
+			want: "ID\tNama\tMulai\tHadir\tJam\n" +
+				"E-1001\tBudi, S.T.\t2025-12-01\t20\t161\n" +
+				"E-1002\tSari\t2025-12-01\t0\t0\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := tt.template
+			if err := Normalize(&template); err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := Write(&buf, template, rows); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Write =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type AbsenceRepository struct {
+	DB *sql.DB
+}
+
+func NewAbsenceRepository(db *sql.DB) *AbsenceRepository {
+	return &AbsenceRepository{DB: db}
+}
+
+// RecordAbsences: menyimpan ketidakhadiran baru. Yang sudah tercatat (nik + tanggal
+// sama) dilewati, jadi hanya baris yang benar-benar baru yang dikembalikan.
+func (repo *AbsenceRepository) RecordAbsences(absences []model.Absence) ([]model.Absence, error) {
+	query := `INSERT INTO absences (nik, date, shift_code, shift_start, shift_end, supervisor_nik)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+        ON CONFLICT (nik, date) DO NOTHING
+        RETURNING id, detected_at`
+
+	recorded := []model.Absence{}
+	for _, a := range absences {
+		err := repo.DB.QueryRow(query, a.NIK, a.Date, a.ShiftCode, a.ShiftStart, a.ShiftEnd, a.SupervisorNIK).Scan(&a.ID, &a.DetectedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return recorded, fmt.Errorf("gagal menyimpan ketidakhadiran %s: %w", a.NIK, err)
+		}
+		recorded = append(recorded, a)
+	}
+	return recorded, nil
+}
+
+// GetAbsences: ketidakhadiran pada rentang [from, to], filter organisasi
+// dicocokkan dengan penugasan pada tanggal ketidakhadiran. Baris di tabel ini
+// adalah snapshot saat deteksi: cuti, catatan atau koreksi yang ditambahkan
+// kemudian tidak menghapusnya, status terkini ada di laporan kehadiran.
+func (repo *AbsenceRepository) GetAbsences(from, to time.Time, filter model.OrgFilter) ([]model.Absence, error) {
+	args := []interface{}{from, to}
+	orgClause, args := orgFilterClause(filter, "a.nik", "a.date", args)
+	query := absenceSelect + `
+        WHERE a.date BETWEEN $1 AND $2` + orgClause + `
+        ORDER BY a.date, a.nik`
+	return repo.queryAbsences(query, args...)
+}
+
+// GetUnnotified: ketidakhadiran sejak tanggal since yang webhook-nya belum
+// berhasil terkirim (notified_at kosong)
+func (repo *AbsenceRepository) GetUnnotified(since time.Time) ([]model.Absence, error) {
+	query := absenceSelect + `
+        WHERE a.date >= $1 AND a.notified_at IS NULL
+        ORDER BY a.date, a.nik`
+	return repo.queryAbsences(query, since)
+}
+
+var absenceSelect = `SELECT a.id, a.nik, COALESCE(u.full_name, ''), COALESCE(d.name, ''), a.date, a.shift_code,
+            a.shift_start, a.shift_end, COALESCE(a.supervisor_nik, ''), a.detected_at, a.notified_at
+        FROM absences a
+        LEFT JOIN users u ON u.nik = a.nik
+        ` + assignmentJoin("cur", "a.nik", "a.date") + `
+        LEFT JOIN departments d ON d.id = cur.department_id`
+
+func (repo *AbsenceRepository) queryAbsences(query string, args ...interface{}) ([]model.Absence, error) {
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query ketidakhadiran: %w", err)
+	}
+	defer rows.Close()
+
+	absences := []model.Absence{}
+	for rows.Next() {
+		var a model.Absence
+		var notifiedAt sql.NullTime
+		err := rows.Scan(&a.ID, &a.NIK, &a.FullName, &a.DepartmentName, &a.Date, &a.ShiftCode,
+			&a.ShiftStart, &a.ShiftEnd, &a.SupervisorNIK, &a.DetectedAt, &notifiedAt)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan ketidakhadiran: %w", err)
+		}
+		a.NotifiedAt = nullTimePtr(notifiedAt)
+		absences = append(absences, a)
+	}
+	return absences, rows.Err()
+}
+
+func (repo *AbsenceRepository) MarkNotified(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := repo.DB.Exec(`UPDATE absences SET notified_at = NOW() WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("gagal menandai notifikasi ketidakhadiran: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAuditReasonRequired: perubahan manual tanpa alasan/aktor tidak boleh disimpan
+var ErrAuditReasonRequired = errors.New("aktor dan alasan perubahan wajib diisi")
+
+type AuditRepository struct {
+	DB *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+// writeAudit: mencatat satu entri audit di dalam transaksi perubahan datanya,
+// sehingga perubahan dan jejaknya selalu tersimpan bersama. before/after nil
+// disimpan sebagai NULL.
+func writeAudit(tx *sql.Tx, audit model.AuditContext, action, nik, date string, before, after interface{}) error {
+	if strings.TrimSpace(audit.Actor) == "" || strings.TrimSpace(audit.Reason) == "" {
+		return ErrAuditReasonRequired
+	}
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO audit_log (actor, action, nik, date, before, after, reason, client_ip)
+        VALUES ($1, $2, $3, $4, $5::jsonb, $6::jsonb, $7, $8)`
+	_, err = tx.Exec(query, audit.Actor, action, nik, date, beforeJSON, afterJSON, strings.TrimSpace(audit.Reason), audit.ClientIP)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan audit: %w", err)
+	}
+	return nil
+}
+
+// auditJSON: snapshot sebagai teks JSON (nil = NULL). Dikirim sebagai string
+// karena []byte akan dikirim driver sebagai bytea.
+func auditJSON(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("gagal serialisasi audit: %w", err)
+	}
+	return string(data), nil
+}
+
+// GetEntries: entri audit terbaru lebih dulu, disaring per karyawan, tanggal data, aktor dan aksi
+func (repo *AuditRepository) GetEntries(filter model.AuditFilter) ([]model.AuditEntry, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.NIK != "" {
+		add("nik = $%d", filter.NIK)
+	}
+	if filter.From != "" {
+		add("date >= $%d::date", filter.From)
+	}
+	if filter.To != "" {
+		add("date <= $%d::date", filter.To)
+	}
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	args = append(args, filter.Limit)
+
+	query := `SELECT id, actor, action, nik, to_char(date, 'YYYY-MM-DD'), before, after, reason, client_ip, created_at
+        FROM audit_log
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY created_at DESC, id DESC
+        LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var e model.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.NIK, &e.Date, &before, &after, &e.Reason, &e.ClientIP, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal scan audit: %w", err)
+		}
+		e.Before, e.After = nullJSON(before), nullJSON(after)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// nullJSON: kolom JSONB NULL ditampilkan sebagai null, bukan string kosong
+func nullJSON(data []byte) json.RawMessage {
+	if data == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(data)
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type CorrectionRepository struct {
+	DB *sql.DB
+}
+
+func NewCorrectionRepository(db *sql.DB) *CorrectionRepository {
+	return &CorrectionRepository{DB: db}
+}
+
+const correctionColumns = `id, nik, action, timestamp, COALESCE(direction, ''), log_id, reason, status,
+    requested_by, assigned_to, COALESCE(decided_by, ''), decided_at, COALESCE(decision_note, ''),
+    applied_log_id, created_at`
+
+func scanCorrection(row rowScanner) (model.CorrectionRequest, error) {
+	var r model.CorrectionRequest
+	var timestamp, decidedAt sql.NullTime
+	var logID, appliedLogID sql.NullInt64
+	err := row.Scan(&r.ID, &r.NIK, &r.Action, &timestamp, &r.Direction, &logID, &r.Reason, &r.Status,
+		&r.RequestedBy, &r.AssignedTo, &r.DecidedBy, &decidedAt, &r.DecisionNote, &appliedLogID, &r.CreatedAt)
+	r.Timestamp = nullTimePtr(timestamp)
+	r.DecidedAt = nullTimePtr(decidedAt)
+	r.LogID = nullInt64Ptr(logID)
+	r.AppliedLogID = nullInt64Ptr(appliedLogID)
+	return r, err
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+func (repo *CorrectionRepository) CreateRequest(data *model.CreateCorrectionRequest, timestamp *time.Time, direction, assignedTo string) (model.CorrectionRequest, error) {
+	var logID interface{}
+	if data.Action == model.CorrectionDelete {
+		logID = data.LogID
+	}
+	query := `INSERT INTO correction_requests (nik, action, timestamp, direction, log_id, reason, requested_by, assigned_to)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8) RETURNING ` + correctionColumns
+	request, err := scanCorrection(repo.DB.QueryRow(query, data.NIK, data.Action, timestamp, direction, logID,
+		data.Reason, data.RequestedBy, assignedTo))
+	if err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal menyimpan pengajuan koreksi: %w", err)
+	}
+	return request, nil
+}
+
+func (repo *CorrectionRepository) GetRequest(id int) (model.CorrectionRequest, error) {
+	query := `SELECT ` + correctionColumns + ` FROM correction_requests WHERE id = $1`
+	request, err := scanCorrection(repo.DB.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return model.CorrectionRequest{}, ErrRequestNotFound
+	}
+	if err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal mengambil pengajuan koreksi: %w", err)
+	}
+	return request, nil
+}
+
+// HasPendingDelete: sudah ada pengajuan hapus yang menunggu untuk log tsb
+func (repo *CorrectionRepository) HasPendingDelete(logID int64) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM correction_requests WHERE log_id = $1 AND status = $2)`
+	if err := repo.DB.QueryRow(query, logID, model.RequestSubmitted).Scan(&exists); err != nil {
+		return false, fmt.Errorf("gagal memeriksa pengajuan koreksi: %w", err)
+	}
+	return exists, nil
+}
+
+func (repo *CorrectionRepository) ListRequests(filter model.CorrectionRequestFilter) ([]model.CorrectionRequest, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.NIK != "" {
+		args = append(args, filter.NIK)
+		conditions = append(conditions, fmt.Sprintf("nik = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.AssignedTo != "" {
+		args = append(args, filter.AssignedTo)
+		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", len(args)))
+	}
+
+	query := `SELECT ` + correctionColumns + ` FROM correction_requests
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY created_at DESC, id DESC`
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query pengajuan koreksi: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []model.CorrectionRequest{}
+	for rows.Next() {
+		r, err := scanCorrection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan pengajuan koreksi: %w", err)
+		}
+		requests = append(requests, r)
+	}
+	return requests, rows.Err()
+}
+
+// Approve: menyetujui pengajuan yang masih submitted dan menerapkannya ke fingerlog
+// dalam satu transaksi, lewat logika yang sama dengan input manual / hapus lunak.
+// audit berisi aktor (penyetuju) dan alasan untuk entri audit perubahan log.
+func (repo *CorrectionRepository) Approve(id int, data *model.DecideRequest, audit model.AuditContext) (model.CorrectionRequest, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	request, err := scanCorrection(tx.QueryRow(`SELECT `+correctionColumns+`
+        FROM correction_requests WHERE id = $1 FOR UPDATE`, id))
+	if err == sql.ErrNoRows {
+		return model.CorrectionRequest{}, ErrRequestNotFound
+	}
+	if err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal mengambil pengajuan koreksi: %w", err)
+	}
+	if request.Status != model.RequestSubmitted {
+		return model.CorrectionRequest{}, ErrRequestNotPending
+	}
+
+	var appliedLogID interface{}
+	switch request.Action {
+	case model.CorrectionAdd:
+		logID, err := insertManualScan(tx, request.NIK, *request.Timestamp, request.Direction, audit)
+		if err != nil {
+			return model.CorrectionRequest{}, err
+		}
+		appliedLogID = logID
+	case model.CorrectionDelete:
+		deleted, err := softDeleteScans(tx, audit, `id = $1 AND nik = $2`, *request.LogID, request.NIK)
+		if err != nil {
+			return model.CorrectionRequest{}, err
+		}
+		if len(deleted) == 0 {
+			return model.CorrectionRequest{}, ErrFingerLogNotFound
+		}
+	}
+
+	query := `UPDATE correction_requests
+        SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = NULLIF($4, ''), applied_log_id = $5
+        WHERE id = $1
+        RETURNING ` + correctionColumns
+	request, err = scanCorrection(tx.QueryRow(query, id, model.RequestApproved, data.DecidedBy, data.Note, appliedLogID))
+	if err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal memperbarui pengajuan koreksi: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal commit pengajuan koreksi: %w", err)
+	}
+	return request, nil
+}
+
+// Reject: menolak pengajuan yang masih berstatus submitted
+func (repo *CorrectionRepository) Reject(id int, data *model.DecideRequest) (model.CorrectionRequest, error) {
+	query := `UPDATE correction_requests
+        SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = NULLIF($4, '')
+        WHERE id = $1 AND status = $5
+        RETURNING ` + correctionColumns
+	request, err := scanCorrection(repo.DB.QueryRow(query, id, model.RequestRejected, data.DecidedBy, data.Note, model.RequestSubmitted))
+	if err == sql.ErrNoRows {
+		if _, getErr := repo.GetRequest(id); getErr != nil {
+			return model.CorrectionRequest{}, getErr
+		}
+		return model.CorrectionRequest{}, ErrRequestNotPending
+	}
+	if err != nil {
+		return model.CorrectionRequest{}, fmt.Errorf("gagal memperbarui pengajuan koreksi: %w", err)
+	}
+	return request, nil
+}
+
+func (repo *CorrectionRepository) AddComment(requestID int, data *model.AddCommentRequest) (model.CorrectionComment, error) {
+	comment := model.CorrectionComment{RequestID: requestID, Author: data.Author, Body: data.Body}
+	query := `INSERT INTO correction_comments (request_id, author, body) VALUES ($1, $2, $3) RETURNING id, created_at`
+	if err := repo.DB.QueryRow(query, requestID, data.Author, data.Body).Scan(&comment.ID, &comment.CreatedAt); err != nil {
+		return model.CorrectionComment{}, fmt.Errorf("gagal menyimpan komentar: %w", err)
+	}
+	return comment, nil
+}
+
+func (repo *CorrectionRepository) GetComments(requestID int) ([]model.CorrectionComment, error) {
+	rows, err := repo.DB.Query(`SELECT id, request_id, author, body, created_at
+        FROM correction_comments WHERE request_id = $1 ORDER BY created_at, id`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query komentar: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []model.CorrectionComment{}
+	for rows.Next() {
+		var c model.CorrectionComment
+		if err := rows.Scan(&c.ID, &c.RequestID, &c.Author, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal scan komentar: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
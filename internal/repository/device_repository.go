@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"fmt"
+)
+
+type DeviceRepository struct {
+	DB *sql.DB
+}
+
+func NewDeviceRepository(db *sql.DB) *DeviceRepository {
+	return &DeviceRepository{DB: db}
+}
+
+// SaveDevice: insert atau update perangkat berdasarkan kode
+func (repo *DeviceRepository) SaveDevice(data *model.SaveDeviceRequest) (model.ScanDevice, error) {
+	device := model.ScanDevice{
+		Code:            data.Code,
+		Name:            data.Name,
+		DebounceSeconds: data.DebounceSeconds,
+		Direction:       data.Direction,
+	}
+	query := `INSERT INTO scan_devices (code, name, debounce_seconds, direction)
+        VALUES ($1, $2, $3, NULLIF($4, ''))
+        ON CONFLICT (code)
+        DO UPDATE SET name = EXCLUDED.name,
+            debounce_seconds = EXCLUDED.debounce_seconds,
+            direction = EXCLUDED.direction
+        RETURNING created_at`
+	err := repo.DB.QueryRow(query, data.Code, data.Name, data.DebounceSeconds, data.Direction).Scan(&device.CreatedAt)
+	if err != nil {
+		return model.ScanDevice{}, fmt.Errorf("gagal menyimpan perangkat: %w", err)
+	}
+	return device, nil
+}
+
+func (repo *DeviceRepository) GetDevices() ([]model.ScanDevice, error) {
+	query := `SELECT code, name, debounce_seconds, COALESCE(direction, ''), created_at FROM scan_devices ORDER BY code`
+	rows, err := repo.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query perangkat: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []model.ScanDevice{}
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// GetDevice: nil jika perangkat belum terdaftar
+func (repo *DeviceRepository) GetDevice(code string) (*model.ScanDevice, error) {
+	query := `SELECT code, name, debounce_seconds, COALESCE(direction, ''), created_at FROM scan_devices WHERE code = $1`
+	d, err := scanDevice(repo.DB.QueryRow(query, code))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDevice(row rowScanner) (model.ScanDevice, error) {
+	var d model.ScanDevice
+	var debounce sql.NullInt64
+	if err := row.Scan(&d.Code, &d.Name, &debounce, &d.Direction, &d.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return d, err
+		}
+		return d, fmt.Errorf("gagal scan perangkat: %w", err)
+	}
+	if debounce.Valid {
+		secs := int(debounce.Int64)
+		d.DebounceSeconds = &secs
+	}
+	return d, nil
+}
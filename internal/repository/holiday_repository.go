@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrHolidayNotFound = errors.New("hari libur tidak ditemukan")
+
+type HolidayRepository struct {
+	DB *sql.DB
+}
+
+func NewHolidayRepository(db *sql.DB) *HolidayRepository {
+	return &HolidayRepository{DB: db}
+}
+
+const holidayColumns = `id, date, name, kind, department_id, COALESCE(site, ''), source, created_at`
+
+func scanHoliday(row rowScanner) (model.Holiday, error) {
+	var h model.Holiday
+	var departmentID sql.NullInt64
+	if err := row.Scan(&h.ID, &h.Date, &h.Name, &h.Kind, &departmentID, &h.Site, &h.Source, &h.CreatedAt); err != nil {
+		return model.Holiday{}, fmt.Errorf("gagal scan hari libur: %w", err)
+	}
+	if departmentID.Valid {
+		id := int(departmentID.Int64)
+		h.DepartmentID = &id
+	}
+	return h, nil
+}
+
+// SaveHolidays: menyimpan beberapa hari libur dalam satu transaksi. Tanggal yang
+// sudah ada pada cakupan (departemen/site) yang sama dilewati.
+func (repo *HolidayRepository) SaveHolidays(holidays []model.Holiday) ([]model.Holiday, int, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, 0, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO holidays (date, name, kind, department_id, site, source)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+        ON CONFLICT (date, COALESCE(department_id, 0), COALESCE(site, '')) DO NOTHING
+        RETURNING ` + holidayColumns
+	saved := []model.Holiday{}
+	skipped := 0
+	for _, h := range holidays {
+		holiday, err := scanHoliday(tx.QueryRow(query, h.Date, h.Name, h.Kind, h.DepartmentID, h.Site, h.Source))
+		if errors.Is(err, sql.ErrNoRows) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("gagal menyimpan hari libur %s: %w", h.Date.Format("2006-01-02"), err)
+		}
+		saved = append(saved, holiday)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("gagal commit hari libur: %w", err)
+	}
+	return saved, skipped, nil
+}
+
+func (repo *HolidayRepository) GetHolidays(filter model.HolidayFilter) ([]model.Holiday, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.Year != 0 {
+		args = append(args, filter.Year)
+		conditions = append(conditions, fmt.Sprintf("EXTRACT(YEAR FROM date) = $%d", len(args)))
+	}
+	if filter.DepartmentID != 0 {
+		args = append(args, filter.DepartmentID)
+		conditions = append(conditions, fmt.Sprintf("(department_id IS NULL OR department_id = $%d)", len(args)))
+	}
+	if filter.Site != "" {
+		args = append(args, filter.Site)
+		conditions = append(conditions, fmt.Sprintf("(site IS NULL OR site = $%d)", len(args)))
+	}
+
+	query := `SELECT ` + holidayColumns + ` FROM holidays
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY date, id`
+	return repo.queryHolidays(query, args...)
+}
+
+// HolidaysBetween: semua hari libur (seluruh cakupan) pada rentang [from, to]
+func (repo *HolidayRepository) HolidaysBetween(from, to time.Time) ([]model.Holiday, error) {
+	query := `SELECT ` + holidayColumns + ` FROM holidays
+        WHERE date BETWEEN $1 AND $2
+        ORDER BY date, id`
+	return repo.queryHolidays(query, from, to)
+}
+
+func (repo *HolidayRepository) queryHolidays(query string, args ...interface{}) ([]model.Holiday, error) {
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query hari libur: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []model.Holiday{}
+	for rows.Next() {
+		h, err := scanHoliday(rows)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+func (repo *HolidayRepository) DeleteHoliday(id int) error {
+	result, err := repo.DB.Exec(`DELETE FROM holidays WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus hari libur: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
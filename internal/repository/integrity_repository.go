@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var ErrCheckpointNotFound = errors.New("checkpoint tidak ditemukan")
+
+type IntegrityRepository struct {
+	DB *sql.DB
+}
+
+func NewIntegrityRepository(db *sql.DB) *IntegrityRepository {
+	return &IntegrityRepository{DB: db}
+}
+
+// chainColumns: kolom yang di-hash per rantai, urutannya harus sama dengan
+// trigger fingerlog_chain / audit_log_chain
+var chainColumns = map[string]string{
+	model.ChainFingerLog: `nik, timestamp, COALESCE(device_code, ''), COALESCE(direction, ''),
+            COALESCE(direction_source, ''), is_duplicate`,
+	model.ChainAudit: `actor, action, nik, date::text, COALESCE(before::text, ''), COALESCE(after::text, ''),
+            reason, client_ip, created_at`,
+}
+
+func chainTable(chain string) (string, error) {
+	if _, ok := chainColumns[chain]; !ok {
+		return "", fmt.Errorf("rantai '%s' tidak dikenal", chain)
+	}
+	return chain, nil
+}
+
+// WalkChain: memanggil fn untuk setiap baris rantai, urut chain_seq, langsung dari cursor
+func (repo *IntegrityRepository) WalkChain(chain string, fn func(model.ChainRecord) error) error {
+	table, err := chainTable(chain)
+	if err != nil {
+		return err
+	}
+	query := `SELECT chain_seq, prev_hash, hash, ` + chainColumns[chain] + `
+        FROM ` + table + `
+        WHERE chain_seq IS NOT NULL
+        ORDER BY chain_seq`
+	rows, err := repo.DB.Query(query)
+	if err != nil {
+		return fmt.Errorf("gagal membaca rantai %s: %w", chain, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record model.ChainRecord
+		if chain == model.ChainFingerLog {
+			record, err = scanFingerLogLink(rows)
+		} else {
+			record, err = scanAuditLink(rows)
+		}
+		if err != nil {
+			return fmt.Errorf("gagal scan rantai %s: %w", chain, err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanFingerLogLink(row rowScanner) (model.ChainRecord, error) {
+	var r model.ChainRecord
+	var nik, device, direction, source string
+	var timestamp time.Time
+	var duplicate bool
+	err := row.Scan(&r.Seq, &r.PrevHash, &r.Hash, &nik, &timestamp, &device, &direction, &source, &duplicate)
+	r.Fields = []string{nik, chainMicros(timestamp), device, direction, source, strconv.FormatBool(duplicate)}
+	return r, err
+}
+
+func scanAuditLink(row rowScanner) (model.ChainRecord, error) {
+	var r model.ChainRecord
+	var actor, action, nik, date, before, after, reason, clientIP string
+	var createdAt time.Time
+	err := row.Scan(&r.Seq, &r.PrevHash, &r.Hash, &actor, &action, &nik, &date, &before, &after, &reason, &clientIP, &createdAt)
+	r.Fields = []string{actor, action, nik, date, before, after, reason, clientIP, chainMicros(createdAt)}
+	return r, err
+}
+
+// chainMicros: pasangan Go dari fungsi SQL chain_micros
+func chainMicros(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro(), 10)
+}
+
+// UnchainedCount: baris yang dibuat sebelum rantai diaktifkan
+func (repo *IntegrityRepository) UnchainedCount(chain string) (int64, error) {
+	table, err := chainTable(chain)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := repo.DB.QueryRow(`SELECT COUNT(*) FROM ` + table + ` WHERE chain_seq IS NULL`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("gagal menghitung baris tanpa rantai: %w", err)
+	}
+	return count, nil
+}
+
+// Head: posisi terakhir rantai menurut chain_heads (seq 0 jika rantai masih kosong)
+func (repo *IntegrityRepository) Head(chain string) (model.ChainHead, error) {
+	head := model.ChainHead{Chain: chain}
+	err := repo.DB.QueryRow(`SELECT seq, hash FROM chain_heads WHERE chain = $1`, chain).Scan(&head.Seq, &head.Hash)
+	if err == sql.ErrNoRows {
+		return head, nil
+	}
+	if err != nil {
+		return head, fmt.Errorf("gagal membaca head rantai: %w", err)
+	}
+	return head, nil
+}
+
+// HeadAt: baris rantai terakhir yang dibuat sebelum instan `before`
+func (repo *IntegrityRepository) HeadAt(chain string, before time.Time) (model.ChainHead, error) {
+	head := model.ChainHead{Chain: chain}
+	table, err := chainTable(chain)
+	if err != nil {
+		return head, err
+	}
+	query := `SELECT chain_seq, hash FROM ` + table + `
+        WHERE chain_seq IS NOT NULL AND chained_at < $1
+        ORDER BY chain_seq DESC
+        LIMIT 1`
+	err = repo.DB.QueryRow(query, before).Scan(&head.Seq, &head.Hash)
+	if err == sql.ErrNoRows {
+		return head, nil
+	}
+	if err != nil {
+		return head, fmt.Errorf("gagal membaca head rantai: %w", err)
+	}
+	return head, nil
+}
+
+// SaveCheckpoint: checkpoint yang sudah ada tidak ditimpa. Mengembalikan
+// checkpoint yang tersimpan (bisa milik proses lain yang lebih dulu).
+func (repo *IntegrityRepository) SaveCheckpoint(checkpoint model.SignedCheckpoint) (model.SignedCheckpoint, error) {
+	query := `INSERT INTO integrity_checkpoints (date, payload, signature, public_key)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (date) DO NOTHING`
+	_, err := repo.DB.Exec(query, checkpoint.Date, string(checkpoint.Payload), checkpoint.Signature, checkpoint.PublicKey)
+	if err != nil {
+		return model.SignedCheckpoint{}, fmt.Errorf("gagal menyimpan checkpoint: %w", err)
+	}
+	return repo.GetCheckpoint(checkpoint.Date)
+}
+
+func (repo *IntegrityRepository) GetCheckpoint(date string) (model.SignedCheckpoint, error) {
+	query := `SELECT to_char(date, 'YYYY-MM-DD'), payload, signature, public_key
+        FROM integrity_checkpoints WHERE date = $1::date`
+	checkpoint, err := scanCheckpoint(repo.DB.QueryRow(query, date))
+	if err == sql.ErrNoRows {
+		return model.SignedCheckpoint{}, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return model.SignedCheckpoint{}, fmt.Errorf("gagal mengambil checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// GetCheckpoints: semua checkpoint, urut tanggal
+func (repo *IntegrityRepository) GetCheckpoints() ([]model.SignedCheckpoint, error) {
+	rows, err := repo.DB.Query(`SELECT to_char(date, 'YYYY-MM-DD'), payload, signature, public_key
+        FROM integrity_checkpoints ORDER BY date`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query checkpoint: %w", err)
+	}
+	defer rows.Close()
+
+	checkpoints := []model.SignedCheckpoint{}
+	for rows.Next() {
+		checkpoint, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+func scanCheckpoint(row rowScanner) (model.SignedCheckpoint, error) {
+	checkpoint := model.SignedCheckpoint{Algorithm: "ed25519"}
+	var payload string
+	err := row.Scan(&checkpoint.Date, &payload, &checkpoint.Signature, &checkpoint.PublicKey)
+	checkpoint.Payload = []byte(payload)
+	return checkpoint, err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeRow: rowScanner dengan nilai kolom tetap
+type fakeRow []interface{}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	for i := range dest {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(r[i]))
+	}
+	return nil
+}
+
+func TestChainMicros(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		// extract(epoch FROM ts) * 1000000 untuk timestamptz dengan presisi mikrodetik
+		{"mikrodetik", time.Date(2025, 12, 14, 7, 58, 3, 123456000, jakarta), "1765673883123456"},
+		{"detik bulat", time.Date(2025, 12, 14, 0, 0, 0, 0, time.UTC), "1765670400000000"},
+		{"zona tidak berpengaruh", time.Date(2025, 12, 14, 7, 0, 0, 0, jakarta), "1765670400000000"},
+		{"epoch", time.Unix(0, 0), "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chainMicros(tt.t); got != tt.want {
+				t.Errorf("chainMicros() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanFingerLogLinkFields(t *testing.T) {
+	ts := time.Date(2025, 12, 14, 7, 58, 3, 123456000, time.FixedZone("WIB", 7*3600))
+	record, err := scanFingerLogLink(fakeRow{int64(1), "prev", "hash", "1001", ts, "", "IN", "manual", true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Urutan dan bentuk teks sama dengan trigger fingerlog_chain (bool::text = "true")
+	want := []string{"1001", "1765673883123456", "", "IN", "manual", "true"}
+	if !reflect.DeepEqual(record.Fields, want) {
+		t.Errorf("Fields = %q, want %q", record.Fields, want)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"Steril-App/internal/sitetime"
 	"Steril-App/model"
 	"database/sql"
 	"fmt"
@@ -25,8 +26,9 @@ func GetDSN() (string, error) {
 	}
 
 	// Format DSN PostgreSQL: "host=... port=... user=... password=... dbname=... sslmode=..."
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.Name, config.SSLMode)
+	// Session Postgres memakai zona site agar konversi tanggal di SQL konsisten dengan aplikasi
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=%s",
+		config.Host, config.Port, config.User, config.Password, config.Name, config.SSLMode, sitetime.Name())
 
 	return dsn, nil
 }
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrLeaveBalanceExceeded = errors.New("sisa cuti tidak mencukupi")
+
+type LeaveRepository struct {
+	DB *sql.DB
+}
+
+func NewLeaveRepository(db *sql.DB) *LeaveRepository {
+	return &LeaveRepository{DB: db}
+}
+
+const leaveColumns = `id, nik, leave_type, start_date, end_date, days, reason, status, requested_by,
+    COALESCE(decided_by, ''), decided_at, COALESCE(decision_note, ''), created_at`
+
+func scanLeaveRequest(row rowScanner) (model.LeaveRequest, error) {
+	var r model.LeaveRequest
+	var decidedAt sql.NullTime
+	err := row.Scan(&r.ID, &r.NIK, &r.LeaveType, &r.StartDate, &r.EndDate, &r.Days, &r.Reason, &r.Status,
+		&r.RequestedBy, &r.DecidedBy, &decidedAt, &r.DecisionNote, &r.CreatedAt)
+	r.DecidedAt = nullTimePtr(decidedAt)
+	return r, err
+}
+
+func (repo *LeaveRepository) CreateRequest(data *model.CreateLeaveRequest, start, end time.Time, days int) (model.LeaveRequest, error) {
+	query := `INSERT INTO leave_requests (nik, leave_type, start_date, end_date, days, reason, requested_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + leaveColumns
+	request, err := scanLeaveRequest(repo.DB.QueryRow(query, data.NIK, data.LeaveType, start, end, days, data.Reason, data.RequestedBy))
+	if err != nil {
+		return model.LeaveRequest{}, fmt.Errorf("gagal menyimpan pengajuan cuti: %w", err)
+	}
+	return request, nil
+}
+
+func (repo *LeaveRepository) GetRequest(id int) (model.LeaveRequest, error) {
+	query := `SELECT ` + leaveColumns + ` FROM leave_requests WHERE id = $1`
+	request, err := scanLeaveRequest(repo.DB.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return model.LeaveRequest{}, ErrRequestNotFound
+	}
+	if err != nil {
+		return model.LeaveRequest{}, fmt.Errorf("gagal mengambil pengajuan cuti: %w", err)
+	}
+	return request, nil
+}
+
+// HasOverlap: ada pengajuan lain (belum ditolak) yang beririsan dengan [start, end]
+func (repo *LeaveRepository) HasOverlap(nik string, start, end time.Time) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM leave_requests
+        WHERE nik = $1 AND status <> $4 AND start_date <= $3 AND end_date >= $2)`
+	if err := repo.DB.QueryRow(query, nik, start, end, model.RequestRejected).Scan(&exists); err != nil {
+		return false, fmt.Errorf("gagal memeriksa pengajuan cuti: %w", err)
+	}
+	return exists, nil
+}
+
+func (repo *LeaveRepository) ListRequests(filter model.LeaveRequestFilter) ([]model.LeaveRequest, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.NIK != "" {
+		args = append(args, filter.NIK)
+		conditions = append(conditions, fmt.Sprintf("nik = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("end_date >= $%d", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("start_date <= $%d", len(args)))
+	}
+
+	query := `SELECT ` + leaveColumns + ` FROM leave_requests
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY start_date DESC, nik`
+	return repo.queryRequests(query, args...)
+}
+
+// ApprovedBetween: cuti disetujui yang beririsan dengan rentang [from, to]
+func (repo *LeaveRepository) ApprovedBetween(from, to time.Time) ([]model.LeaveRequest, error) {
+	query := `SELECT ` + leaveColumns + ` FROM leave_requests
+        WHERE status = $1 AND start_date <= $3 AND end_date >= $2`
+	return repo.queryRequests(query, model.RequestApproved, from, to)
+}
+
+func (repo *LeaveRepository) queryRequests(query string, args ...interface{}) ([]model.LeaveRequest, error) {
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query pengajuan cuti: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []model.LeaveRequest{}
+	for rows.Next() {
+		r, err := scanLeaveRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan pengajuan cuti: %w", err)
+		}
+		requests = append(requests, r)
+	}
+	return requests, rows.Err()
+}
+
+// Approve: menyetujui pengajuan dalam satu transaksi. Untuk jenis cuti yang
+// memotong saldo, baris leave_balances dikunci (FOR UPDATE) sebelum sisa dihitung,
+// jadi dua persetujuan bersamaan tidak bisa sama-sama lolos melebihi hak cuti.
+// Saldo dikembalikan bersama ErrLeaveBalanceExceeded untuk pesan kesalahan.
+func (repo *LeaveRepository) Approve(id int, data *model.DecideRequest) (model.LeaveRequest, model.LeaveBalance, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.LeaveRequest{}, model.LeaveBalance{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	request, err := scanLeaveRequest(tx.QueryRow(`SELECT `+leaveColumns+` FROM leave_requests WHERE id = $1 FOR UPDATE`, id))
+	if err == sql.ErrNoRows {
+		return model.LeaveRequest{}, model.LeaveBalance{}, ErrRequestNotFound
+	}
+	if err != nil {
+		return model.LeaveRequest{}, model.LeaveBalance{}, fmt.Errorf("gagal mengambil pengajuan cuti: %w", err)
+	}
+	if request.Status != model.RequestSubmitted {
+		return model.LeaveRequest{}, model.LeaveBalance{}, ErrRequestNotPending
+	}
+
+	var balance model.LeaveBalance
+	if leaveType := model.FindLeaveType(request.LeaveType); leaveType != nil && leaveType.DeductsBalance {
+		year := request.StartDate.Year()
+		// Baris hak default dibuat dulu agar selalu ada yang bisa dikunci
+		lock := `INSERT INTO leave_balances (nik, year, entitled_days) VALUES ($1, $2, $3)
+            ON CONFLICT (nik, year) DO NOTHING`
+		if _, err := tx.Exec(lock, request.NIK, year, model.DefaultAnnualLeaveDays); err != nil {
+			return model.LeaveRequest{}, model.LeaveBalance{}, fmt.Errorf("gagal mengunci saldo cuti: %w", err)
+		}
+		if _, err := tx.Exec(`SELECT 1 FROM leave_balances WHERE nik = $1 AND year = $2 FOR UPDATE`, request.NIK, year); err != nil {
+			return model.LeaveRequest{}, model.LeaveBalance{}, fmt.Errorf("gagal mengunci saldo cuti: %w", err)
+		}
+		balance, err = scanBalance(tx.QueryRow(balanceQuery, balanceArgs(request.NIK, year)...), request.NIK, year)
+		if err != nil {
+			return model.LeaveRequest{}, model.LeaveBalance{}, err
+		}
+		if request.Days > balance.Remaining {
+			return request, balance, ErrLeaveBalanceExceeded
+		}
+	}
+
+	query := `UPDATE leave_requests
+        SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = NULLIF($4, '')
+        WHERE id = $1
+        RETURNING ` + leaveColumns
+	request, err = scanLeaveRequest(tx.QueryRow(query, id, model.RequestApproved, data.DecidedBy, data.Note))
+	if err != nil {
+		return model.LeaveRequest{}, model.LeaveBalance{}, fmt.Errorf("gagal memperbarui pengajuan cuti: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return model.LeaveRequest{}, model.LeaveBalance{}, fmt.Errorf("gagal commit pengajuan cuti: %w", err)
+	}
+	return request, balance, nil
+}
+
+// Decide: menyetujui/menolak pengajuan yang masih berstatus submitted
+func (repo *LeaveRepository) Decide(id int, status string, data *model.DecideRequest) (model.LeaveRequest, error) {
+	query := `UPDATE leave_requests
+        SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = NULLIF($4, '')
+        WHERE id = $1 AND status = $5
+        RETURNING ` + leaveColumns
+	request, err := scanLeaveRequest(repo.DB.QueryRow(query, id, status, data.DecidedBy, data.Note, model.RequestSubmitted))
+	if err == sql.ErrNoRows {
+		if _, getErr := repo.GetRequest(id); getErr != nil {
+			return model.LeaveRequest{}, getErr
+		}
+		return model.LeaveRequest{}, ErrRequestNotPending
+	}
+	if err != nil {
+		return model.LeaveRequest{}, fmt.Errorf("gagal memperbarui pengajuan cuti: %w", err)
+	}
+	return request, nil
+}
+
+// GetBalance: hak cuti tahunan beserta pemakaian. Cuti yang melewati pergantian
+// tahun dihitung pada tahun tanggal mulainya.
+func (repo *LeaveRepository) GetBalance(nik string, year int) (model.LeaveBalance, error) {
+	return scanBalance(repo.DB.QueryRow(balanceQuery, balanceArgs(nik, year)...), nik, year)
+}
+
+const balanceQuery = `SELECT
+            COALESCE((SELECT entitled_days FROM leave_balances WHERE nik = $1 AND year = $2), $3),
+            COALESCE(SUM(days) FILTER (WHERE status = $5), 0),
+            COALESCE(SUM(days) FILTER (WHERE status = $6), 0)
+        FROM leave_requests
+        WHERE nik = $1 AND leave_type = $4 AND EXTRACT(YEAR FROM start_date) = $2`
+
+func balanceArgs(nik string, year int) []interface{} {
+	return []interface{}{nik, year, model.DefaultAnnualLeaveDays, model.LeaveAnnual, model.RequestApproved, model.RequestSubmitted}
+}
+
+func scanBalance(row rowScanner, nik string, year int) (model.LeaveBalance, error) {
+	balance := model.LeaveBalance{NIK: nik, Year: year}
+	if err := row.Scan(&balance.Entitled, &balance.Used, &balance.Pending); err != nil {
+		return balance, fmt.Errorf("gagal menghitung saldo cuti: %w", err)
+	}
+	balance.Remaining = balance.Entitled - balance.Used
+	return balance, nil
+}
+
+func (repo *LeaveRepository) SetBalance(nik string, data *model.SetLeaveBalanceRequest) error {
+	query := `INSERT INTO leave_balances (nik, year, entitled_days) VALUES ($1, $2, $3)
+        ON CONFLICT (nik, year) DO UPDATE SET entitled_days = EXCLUDED.entitled_days, updated_at = NOW()`
+	if _, err := repo.DB.Exec(query, nik, data.Year, data.EntitledDays); err != nil {
+		return fmt.Errorf("gagal menyimpan hak cuti: %w", err)
+	}
+	return nil
+}
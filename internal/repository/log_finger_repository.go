@@ -1,13 +1,23 @@
 package repository
 
 import (
+	"Steril-App/internal/sitetime"
 	"Steril-App/model"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 )
 
+var (
+	// ErrFingerLogNotFound: log tidak ada atau sudah dihapus (lunak)
+	ErrFingerLogNotFound = errors.New("data tidak ditemukan atau sudah terhapus")
+	ErrRestoreExpired    = errors.New("masa retensi log terhapus sudah lewat")
+	ErrFingerLogReplaced = errors.New("log sudah dikoreksi, pulihkan tidak diizinkan")
+	ErrNoteNotFound      = errors.New("catatan tidak ditemukan")
+)
+
 type FingerLogRepository struct {
 	DB *sql.DB
 }
@@ -37,161 +47,560 @@ func (repo *FingerLogRepository) AddFingerLog(nik string) error {
 	return nil
 }
 
-func (repo *FingerLogRepository) AddManualFingerLog(nik string, timestamp time.Time) error {
-    // Kita insert NIK dan TIMESTAMP sesuai input
-    query := `INSERT INTO fingerlog (nik, timestamp) VALUES ($1, $2)`
-    
-    result, err := repo.DB.Exec(query, nik, timestamp)
-    if err != nil {
-        log.Printf("Error insert manual: %v", err)
-        return fmt.Errorf("gagal insert log finger manual: %w", err)
-    }
+// duplicateScan: kondisi SQL "NIK $1 sudah punya scan (bukan duplikat) dalam
+// jendela debounce perangkat $2", default $3 detik jika perangkat tidak diatur
+const duplicateScan = `EXISTS (
+            SELECT 1 FROM fingerlog f
+            WHERE f.nik = $1
+              AND NOT f.is_duplicate
+              AND f.deleted_at IS NULL
+              AND f.timestamp > NOW() - make_interval(secs =>
+                  COALESCE((SELECT debounce_seconds FROM scan_devices WHERE code = $2), $3::int))
+        )`
+
+// AddScan: menyimpan scan dari perangkat. Jika NIK yang sama sudah punya scan
+// (bukan duplikat) dalam jendela debounce, scan tetap disimpan tetapi ditandai
+// is_duplicate sehingga tidak ikut laporan. Jendela debounce diambil dari
+// scan_devices untuk perangkat tsb, atau defaultSeconds jika tidak diatur.
+// Arah hasil tebakan server tidak disimpan untuk scan duplikat.
+func (repo *FingerLogRepository) AddScan(nik, device, direction, directionSource string, defaultSeconds int) (bool, error) {
+	query := `WITH dup AS (SELECT ` + duplicateScan + ` AS is_duplicate)
+        INSERT INTO fingerlog (nik, device_code, direction, direction_source, is_duplicate)
+        SELECT $1, NULLIF($2, ''),
+            CASE WHEN dup.is_duplicate AND $5 = 'inferred' THEN NULL ELSE NULLIF($4, '') END,
+            CASE WHEN dup.is_duplicate AND $5 = 'inferred' THEN NULL ELSE NULLIF($5, '') END,
+            dup.is_duplicate
+        FROM dup
+        RETURNING is_duplicate`
+
+	var isDuplicate bool
+	if err := repo.DB.QueryRow(query, nik, device, defaultSeconds, direction, directionSource).Scan(&isDuplicate); err != nil {
+		return false, fmt.Errorf("gagal insert log finger: %w", err)
+	}
+	return isDuplicate, nil
+}
 
-    rowsAffected, err := result.RowsAffected()
+// IsDuplicateScan: apakah scan NIK dari perangkat tsb saat ini akan ditandai
+// duplikat oleh AddScan
+func (repo *FingerLogRepository) IsDuplicateScan(nik, device string, defaultSeconds int) (bool, error) {
+	var duplicate bool
+	if err := repo.DB.QueryRow(`SELECT `+duplicateScan, nik, device, defaultSeconds).Scan(&duplicate); err != nil {
+		return false, fmt.Errorf("gagal memeriksa scan duplikat: %w", err)
+	}
+	return duplicate, nil
+}
+
+// LastScan: scan terakhir (bukan duplikat) milik NIK, nil jika belum pernah scan
+func (repo *FingerLogRepository) LastScan(nik string) (*model.FingerLogEntry, error) {
+	query := `SELECT timestamp, COALESCE(direction, '') FROM fingerlog_clean
+        WHERE nik = $1
+        ORDER BY timestamp DESC
+        LIMIT 1`
+	var entry model.FingerLogEntry
+	err := repo.DB.QueryRow(query, nik).Scan(&entry.Timestamp, &entry.Direction)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil scan terakhir: %w", err)
+	}
+	return &entry, nil
+}
+
+// AddManualFingerLog: insert scan manual beserta entri audit dalam satu transaksi
+func (repo *FingerLogRepository) AddManualFingerLog(nik string, timestamp time.Time, direction string, audit model.AuditContext) error {
+    tx, err := repo.DB.Begin()
     if err != nil {
-        return fmt.Errorf("gagal cek rows affected: %v", err)
+        return fmt.Errorf("gagal memulai transaksi: %w", err)
     }
+    defer tx.Rollback()
 
-    if rowsAffected == 0 {
-        return fmt.Errorf("gagal insert: 0 baris terpengaruh")
+    if _, err := insertManualScan(tx, nik, timestamp, direction, audit); err != nil {
+        return err
     }
-    
-    return nil
-}
-
-func (repo *FingerLogRepository) GetFingerLog(date string) ([]model.FingerLogResult, error) {
-	query := `SELECT
-    u.nik,
-    u.full_name,
-    f.timestamp
-    FROM
-        fingerlog f
-    JOIN
-        users u ON f.nik = u.nik
-    WHERE
-        f.timestamp::date = $1
-    ORDER BY
-        u.nik ASC,
-        f.timestamp ASC;`
-
-	// Query ke database
-	result, err := repo.DB.Query(query, date)
+    return tx.Commit()
+}
+
+// insertManualScan: insert scan manual dan audit-nya di dalam transaksi tx,
+// mengembalikan ID log baru. Dipakai juga saat pengajuan koreksi disetujui.
+func insertManualScan(tx *sql.Tx, nik string, timestamp time.Time, direction string, audit model.AuditContext) (int64, error) {
+	// Kita insert NIK dan TIMESTAMP sesuai input
+	query := `INSERT INTO fingerlog (nik, timestamp, direction, direction_source)
+        VALUES ($1, $2, NULLIF($3, ''), CASE WHEN $3 = '' THEN NULL ELSE 'manual' END)
+        RETURNING id`
+
+	var id int64
+	if err := tx.QueryRow(query, nik, timestamp, direction).Scan(&id); err != nil {
+		log.Printf("Error insert manual: %v", err)
+		return 0, fmt.Errorf("gagal insert log finger manual: %w", err)
+	}
+
+	after := model.AuditScan{ID: id, NIK: nik, Timestamp: timestamp, Direction: direction}
+	if err := writeAudit(tx, audit, model.AuditFingerLogInsert, nik, sitetime.FormatDate(timestamp), nil, after); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetActiveScan: satu log (belum terhapus) berdasarkan ID
+func (repo *FingerLogRepository) GetActiveScan(id int64) (model.AuditScan, error) {
+	var scan model.AuditScan
+	err := repo.DB.QueryRow(`SELECT id, nik, timestamp, COALESCE(direction, '')
+        FROM fingerlog WHERE id = $1 AND deleted_at IS NULL`, id).
+		Scan(&scan.ID, &scan.NIK, &scan.Timestamp, &scan.Direction)
+	if err == sql.ErrNoRows {
+		return scan, ErrFingerLogNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error executing query: %w", err)
+		return scan, fmt.Errorf("gagal mengambil log finger: %w", err)
 	}
-	defer result.Close()
+	return scan, nil
+}
 
-	// 1. Slice untuk menampung hasil akhir (Agar urutan terjaga)
-	var data []model.FingerLogResult
+// siteDate: tanggal (zona site) dari kolom timestamp. tzParam adalah nomor
+// parameter yang berisi sitetime.Name().
+func siteDate(column string, tzParam int) string {
+	return fmt.Sprintf("(%s AT TIME ZONE $%d)::date", column, tzParam)
+}
 
-	// 2. Map 'pembantu' untuk melacak posisi index berdasarkan NIK
-	// Key: NIK, Value: Index di dalam slice 'data'
-	indices := make(map[string]int)
+// SaveUserNote: simpan/ubah catatan harian. Setiap perubahan disimpan sebagai
+// revisi di note_revisions, isi lama dan baru juga dicatat di audit.
+func (repo *FingerLogRepository) SaveUserNote(nik, date, category, note string, audit model.AuditContext) error {
+    tx, err := repo.DB.Begin()
+    if err != nil {
+        return fmt.Errorf("gagal memulai transaksi: %w", err)
+    }
+    defer tx.Rollback()
 
-	for result.Next() {
-		var row model.RawFingerLog
+    if _, err := saveNote(tx, model.NoteTarget{NIK: nik, Date: date}, category, note, true, audit); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
 
-		if err := result.Scan(&row.NIK, &row.FullName, &row.Timestamp); err != nil {
-			return nil, fmt.Errorf("gagal scan data :%w", err)
-		}
+// SaveNotes: satu catatan untuk banyak NIK/tanggal dalam satu transaksi.
+// overwrite = false melewati target yang sudah punya catatan.
+func (repo *FingerLogRepository) SaveNotes(targets []model.NoteTarget, category, note string, overwrite bool, audit model.AuditContext) (int, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
 
-		// Cek apakah NIK ini sudah pernah kita masukkan ke slice 'data'?
-		if idx, exists := indices[row.NIK]; exists {
-			// KASUS: SUDAH ADA
-			// Kita ambil data di slice berdasarkan index-nya, lalu append timestamp
-			data[idx].Timestamps = append(data[idx].Timestamps, row.Timestamp)
-
-			// fmt.Println("Append data ke:", row.NIK)
-		} else {
-			// KASUS: BELUM ADA (Data Baru)
-			newEntry := model.FingerLogResult{
-				NIK:        row.NIK,
-				FullName:   row.FullName,
-				Timestamps: []time.Time{row.Timestamp},
-			}
-
-			// Masukkan ke slice utama
-			data = append(data, newEntry)
-
-			// Catat posisi index-nya di map
-			// len(data)-1 adalah index elemen yang baru saja kita masukkan
-			indices[row.NIK] = len(data) - 1
-
-			// fmt.Println("Buat entry baru:", row.NIK)
+	applied := 0
+	for _, target := range targets {
+		saved, err := saveNote(tx, target, category, note, overwrite, audit)
+		if err != nil {
+			return 0, err
+		}
+		if saved {
+			applied++
 		}
 	}
-
-	// Tidak perlu looping map lagi disini, karena 'data' sudah terisi rapi & urut
-	return data, nil
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("gagal commit catatan: %w", err)
+	}
+	return applied, nil
 }
 
-func (repo *FingerLogRepository) SaveUserNote(nik, date, note string) error {
+// saveNote: upsert satu catatan beserta revisi dan audit-nya di dalam tx.
+// Mengembalikan false jika catatan sudah ada dan overwrite = false.
+func saveNote(tx *sql.Tx, target model.NoteTarget, category, note string, overwrite bool, audit model.AuditContext) (bool, error) {
+    // Catatan lama dikunci agar snapshot "before" sama dengan yang ditimpa
+    var before interface{}
+    var old model.AuditNote
+    err := tx.QueryRow(`SELECT category, detail FROM detaillog WHERE nik = $1 AND date = $2 FOR UPDATE`, target.NIK, target.Date).
+        Scan(&old.Category, &old.Note)
+    if err != nil && err != sql.ErrNoRows {
+        return false, fmt.Errorf("gagal membaca note lama: %w", err)
+    }
+    if err == nil {
+        if !overwrite {
+            return false, nil
+        }
+        before = old
+    }
+
     // Syntax PostgreSQL untuk UPSERT:
     // Jika kombinasi (nik, date) belum ada -> INSERT
-    // Jika sudah ada (konflik) -> UPDATE kolom detail-nya saja
+    // Jika sudah ada (konflik) -> UPDATE isi, kategori dan pengubahnya
     query := `
-        INSERT INTO detaillog (nik, date, detail)
-        VALUES ($1, $2, $3)
+        INSERT INTO detaillog (nik, date, detail, category, updated_by, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
         ON CONFLICT (nik, date) 
-        DO UPDATE SET detail = EXCLUDED.detail;
+        DO UPDATE SET detail = EXCLUDED.detail, category = EXCLUDED.category,
+            updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at;
     `
 
-    _, err := repo.DB.Exec(query, nik, date, note)
-    if err != nil {
-        return fmt.Errorf("gagal menyimpan note ke database: %w", err)
+    if _, err := tx.Exec(query, target.NIK, target.Date, note, category, audit.Actor); err != nil {
+        return false, fmt.Errorf("gagal menyimpan note ke database: %w", err)
     }
-    return nil
+    if err := writeNoteRevision(tx, target, model.AuditNote{Category: category, Note: note}, false, audit); err != nil {
+        return false, err
+    }
+    after := model.AuditNote{Category: category, Note: note}
+    if err := writeAudit(tx, audit, model.AuditNoteSave, target.NIK, target.Date, before, after); err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+func writeNoteRevision(tx *sql.Tx, target model.NoteTarget, note model.AuditNote, deleted bool, audit model.AuditContext) error {
+	_, err := tx.Exec(`INSERT INTO note_revisions (nik, date, category, detail, author, reason, deleted)
+        VALUES ($1, $2::date, $3, $4, $5, $6, $7)`, target.NIK, target.Date, note.Category, note.Note, audit.Actor, audit.Reason, deleted)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan revisi note: %w", err)
+	}
+	return nil
 }
 
-// GetNotesByDate: Mengambil semua notes pada tanggal tertentu
-func (repo *FingerLogRepository) GetNotesByDate(date string) ([]model.NoteResponse, error) {
-    query := `SELECT nik, detail FROM detaillog WHERE date = $1`
+// DeleteUserNote: hapus catatan harian beserta metadata lampirannya. Isi
+// terakhir disimpan sebagai revisi penghapusan dan dicatat di audit. Lampiran
+// yang terhapus dikembalikan agar file-nya dihapus dari blob store.
+func (repo *FingerLogRepository) DeleteUserNote(nik, date string, audit model.AuditContext) ([]model.NoteAttachment, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	var old model.AuditNote
+	err = tx.QueryRow(`DELETE FROM detaillog WHERE nik = $1 AND date = $2 RETURNING category, detail`, nik, date).
+		Scan(&old.Category, &old.Note)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghapus note: %w", err)
+	}
+
+	attachments, err := deleteAttachments(tx, audit, `nik = $1 AND date = $2::date`, nik, date)
+	if err != nil {
+		return nil, err
+	}
+	target := model.NoteTarget{NIK: nik, Date: date}
+	if err := writeNoteRevision(tx, target, old, true, audit); err != nil {
+		return nil, err
+	}
+	if err := writeAudit(tx, audit, model.AuditNoteDelete, nik, date, old, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("gagal commit hapus note: %w", err)
+	}
+	return attachments, nil
+}
 
-    rows, err := repo.DB.Query(query, date)
+// GetNoteRevisions: riwayat catatan satu NIK pada satu tanggal, terlama dulu
+func (repo *FingerLogRepository) GetNoteRevisions(nik, date string) ([]model.NoteRevision, error) {
+    query := `SELECT id, nik, to_char(date, 'YYYY-MM-DD'), category, detail, author, reason, deleted, created_at
+        FROM note_revisions WHERE nik = $1 AND date = $2::date
+        ORDER BY created_at, id`
+    rows, err := repo.DB.Query(query, nik, date)
     if err != nil {
-        return nil, fmt.Errorf("gagal query notes: %w", err)
+        return nil, fmt.Errorf("gagal query revisi notes: %w", err)
     }
     defer rows.Close()
 
-    var notes []model.NoteResponse
+    revisions := []model.NoteRevision{}
     for rows.Next() {
-        var n model.NoteResponse
-        // Kita hanya butuh NIK dan isinya (detail) untuk mapping di Frontend
-        if err := rows.Scan(&n.NIK, &n.Note); err != nil {
-            return nil, fmt.Errorf("gagal scan row notes: %w", err)
+        var r model.NoteRevision
+        if err := rows.Scan(&r.ID, &r.NIK, &r.Date, &r.Category, &r.Note, &r.Author, &r.Reason, &r.Deleted, &r.CreatedAt); err != nil {
+            return nil, fmt.Errorf("gagal scan revisi notes: %w", err)
         }
-        notes = append(notes, n)
+        revisions = append(revisions, r)
     }
-    
-    // Kembalikan slice kosong [] jika tidak ada data (bukan nil) agar JSON-nya "[]"
-    if notes == nil {
-        notes = []model.NoteResponse{}
-    }
-
-    return notes, nil
+    return revisions, rows.Err()
 }
 
-func (repo *FingerLogRepository) DeleteFingerLog(nik string, timestamp time.Time) error {
-    // Query delete berdasarkan NIK dan Timestamp persis
-    query := `DELETE FROM fingerlog WHERE nik = $1 AND timestamp = $2`
-
-    result, err := repo.DB.Exec(query, nik, timestamp)
+// DeleteFingerLog: hapus lunak scan berdasarkan NIK dan timestamp persis.
+// Dipertahankan untuk klien lama; klien baru memakai SoftDeleteFingerLog (by ID).
+func (repo *FingerLogRepository) DeleteFingerLog(nik string, timestamp time.Time, audit model.AuditContext) error {
+    tx, err := repo.DB.Begin()
     if err != nil {
-        log.Printf("Error deleting log: %v", err)
-        return fmt.Errorf("gagal menghapus data: %w", err)
+        return fmt.Errorf("gagal memulai transaksi: %w", err)
     }
+    defer tx.Rollback()
 
-    rowsAffected, err := result.RowsAffected()
+    deleted, err := softDeleteScans(tx, audit, `nik = $1 AND timestamp = $2`, nik, timestamp)
     if err != nil {
-        return fmt.Errorf("gagal cek rows affected: %v", err)
+        return err
     }
-
     // Jika 0, berarti tidak ada data yang cocok (mungkin salah detik atau salah jam)
-    if rowsAffected == 0 {
-        return fmt.Errorf("data tidak ditemukan atau sudah terhapus")
+    if len(deleted) == 0 {
+        return ErrFingerLogNotFound
     }
+    return tx.Commit()
+}
+
+// SoftDeleteFingerLog: hapus lunak satu log berdasarkan ID, dicatat di audit
+func (repo *FingerLogRepository) SoftDeleteFingerLog(id int64, audit model.AuditContext) (model.AuditScan, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := softDeleteScans(tx, audit, `id = $1`, id)
+	if err != nil {
+		return model.AuditScan{}, err
+	}
+	if len(deleted) == 0 {
+		return model.AuditScan{}, ErrFingerLogNotFound
+	}
+	return deleted[0], tx.Commit()
+}
+
+// CorrectFingerLog: koreksi jam (dan arah) satu log. Log lama dihapus lunak dan
+// menunjuk ke log pengganti lewat replaced_by; keduanya dicatat sebagai satu
+// entri audit. direction kosong = arah log lama dipertahankan.
+func (repo *FingerLogRepository) CorrectFingerLog(id int64, timestamp time.Time, direction string, audit model.AuditContext) (model.AuditScan, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	var old model.AuditScan
+	var device, source sql.NullString
+	err = tx.QueryRow(`UPDATE fingerlog
+        SET deleted_at = NOW(), deleted_by = $2, delete_reason = $3
+        WHERE id = $1 AND deleted_at IS NULL
+        RETURNING id, nik, timestamp, COALESCE(direction, ''), device_code, direction_source`, id, audit.Actor, audit.Reason).
+		Scan(&old.ID, &old.NIK, &old.Timestamp, &old.Direction, &device, &source)
+	if err == sql.ErrNoRows {
+		return model.AuditScan{}, ErrFingerLogNotFound
+	}
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal menandai log lama: %w", err)
+	}
 
-    return nil
+	// Arah yang diwarisi dari log lama tetap membawa sumbernya (sensor, device, inferred);
+	// hanya arah yang diisi pemanggil yang bersumber 'manual'
+	corrected := model.AuditScan{NIK: old.NIK, Timestamp: timestamp, Direction: direction}
+	if corrected.Direction == "" {
+		corrected.Direction = old.Direction
+	} else {
+		source = sql.NullString{String: "manual", Valid: true}
+	}
+	err = tx.QueryRow(`INSERT INTO fingerlog (nik, timestamp, device_code, direction, direction_source)
+        VALUES ($1, $2, $3, NULLIF($4, ''), CASE WHEN $4 = '' THEN NULL ELSE $5 END)
+        RETURNING id`, corrected.NIK, corrected.Timestamp, device, corrected.Direction, source).Scan(&corrected.ID)
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal menyimpan log koreksi: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE fingerlog SET replaced_by = $2 WHERE id = $1`, old.ID, corrected.ID); err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal menautkan log koreksi: %w", err)
+	}
+
+	if err := writeAudit(tx, audit, model.AuditFingerLogCorrect, old.NIK, sitetime.FormatDate(old.Timestamp), old, corrected); err != nil {
+		return model.AuditScan{}, err
+	}
+	return corrected, tx.Commit()
 }
 
+const deletedColumns = `f.id, f.nik, COALESCE(u.full_name, ''), f.timestamp, COALESCE(f.direction, ''),
+    f.deleted_at, COALESCE(f.deleted_by, ''), COALESCE(f.delete_reason, ''), f.replaced_by`
 
+func scanDeletedFingerLog(row rowScanner) (model.DeletedFingerLog, error) {
+	var d model.DeletedFingerLog
+	var replacedBy sql.NullInt64
+	err := row.Scan(&d.ID, &d.NIK, &d.FullName, &d.Timestamp, &d.Direction, &d.DeletedAt, &d.DeletedBy, &d.DeleteReason, &replacedBy)
+	if replacedBy.Valid {
+		d.ReplacedBy = &replacedBy.Int64
+	}
+	return d, err
+}
+
+// GetDeletedFingerLogs: log terhapus, terbaru dihapus lebih dulu. Parameter nil
+// tidak dipakai sebagai filter: start/end membatasi waktu scan, deletedSince
+// membatasi waktu hapus.
+func (repo *FingerLogRepository) GetDeletedFingerLogs(nik string, start, end, deletedSince *time.Time) ([]model.DeletedFingerLog, error) {
+	query := `SELECT ` + deletedColumns + `
+        FROM fingerlog f
+        LEFT JOIN users u ON u.nik = f.nik
+        WHERE f.deleted_at IS NOT NULL
+          AND ($1 = '' OR f.nik = $1)
+          AND ($2::timestamptz IS NULL OR f.timestamp >= $2)
+          AND ($3::timestamptz IS NULL OR f.timestamp < $3)
+          AND ($4::timestamptz IS NULL OR f.deleted_at >= $4)
+        ORDER BY f.deleted_at DESC, f.id DESC`
+	rows, err := repo.DB.Query(query, nik, start, end, deletedSince)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query log terhapus: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []model.DeletedFingerLog{}
+	for rows.Next() {
+		d, err := scanDeletedFingerLog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan log terhapus: %w", err)
+		}
+		logs = append(logs, d)
+	}
+	return logs, rows.Err()
+}
+
+// RestoreFingerLog: memulihkan log terhapus yang dihapus setelah `deletedSince`
+// dan bukan hasil koreksi. Snapshot penghapusan dicatat sebagai "before" di audit.
+func (repo *FingerLogRepository) RestoreFingerLog(id int64, deletedSince time.Time, audit model.AuditContext) (model.AuditScan, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := scanDeletedFingerLog(tx.QueryRow(`SELECT `+deletedColumns+`
+        FROM fingerlog f
+        LEFT JOIN users u ON u.nik = f.nik
+        WHERE f.id = $1 AND f.deleted_at IS NOT NULL
+        FOR UPDATE OF f`, id))
+	if err == sql.ErrNoRows {
+		return model.AuditScan{}, ErrFingerLogNotFound
+	}
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal mengambil log terhapus: %w", err)
+	}
+	if deleted.ReplacedBy != nil {
+		return model.AuditScan{}, ErrFingerLogReplaced
+	}
+	if deleted.DeletedAt.Before(deletedSince) {
+		return model.AuditScan{}, ErrRestoreExpired
+	}
+
+	_, err = tx.Exec(`UPDATE fingerlog SET deleted_at = NULL, deleted_by = NULL, delete_reason = NULL WHERE id = $1`, id)
+	if err != nil {
+		return model.AuditScan{}, fmt.Errorf("gagal memulihkan log: %w", err)
+	}
+
+	restored := model.AuditScan{ID: deleted.ID, NIK: deleted.NIK, Timestamp: deleted.Timestamp, Direction: deleted.Direction}
+	if err := writeAudit(tx, audit, model.AuditFingerLogRestore, deleted.NIK, sitetime.FormatDate(deleted.Timestamp), deleted, restored); err != nil {
+		return model.AuditScan{}, err
+	}
+	return restored, tx.Commit()
+}
+
+// softDeleteScans: menandai log yang cocok dengan kondisi `where` sebagai terhapus
+// dan mencatat setiap log di audit. Log yang sudah terhapus dilewati.
+func softDeleteScans(tx *sql.Tx, audit model.AuditContext, where string, args ...interface{}) ([]model.AuditScan, error) {
+	n := len(args)
+	query := fmt.Sprintf(`UPDATE fingerlog
+        SET deleted_at = NOW(), deleted_by = $%d, delete_reason = $%d
+        WHERE %s AND deleted_at IS NULL
+        RETURNING id, nik, timestamp, COALESCE(direction, '')`, n+1, n+2, where)
+
+	rows, err := tx.Query(query, append(args, audit.Actor, audit.Reason)...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghapus data: %w", err)
+	}
+	var deleted []model.AuditScan
+	for rows.Next() {
+		var scan model.AuditScan
+		if err := rows.Scan(&scan.ID, &scan.NIK, &scan.Timestamp, &scan.Direction); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal membaca data terhapus: %w", err)
+		}
+		deleted = append(deleted, scan)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("gagal menghapus data: %w", err)
+	}
+
+	for _, scan := range deleted {
+		if err := writeAudit(tx, audit, model.AuditFingerLogDelete, scan.NIK, sitetime.FormatDate(scan.Timestamp), scan, nil); err != nil {
+			return nil, err
+		}
+	}
+	return deleted, nil
+}
+
+// GetScansBetween: semua scan di rentang waktu [start, end), urut per NIK lalu waktu.
+// nik kosong = semua karyawan.
+func (repo *FingerLogRepository) GetScansBetween(start, end time.Time, nik string, filter model.OrgFilter) ([]model.RawFingerLog, error) {
+	args := []interface{}{start, end, sitetime.Name(), nik}
+	orgClause, args := orgFilterClause(filter, "u.nik", siteDate("f.timestamp", 3), args)
+	query := `SELECT f.id, u.nik, u.full_name, f.timestamp, COALESCE(f.direction, '')
+        FROM fingerlog_clean f
+        JOIN users u ON f.nik = u.nik
+        WHERE f.timestamp >= $1 AND f.timestamp < $2
+          AND ($4 = '' OR f.nik = $4)` + orgClause + `
+        ORDER BY u.nik ASC, f.timestamp ASC`
+
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	var scans []model.RawFingerLog
+	for rows.Next() {
+		var row model.RawFingerLog
+		if err := rows.Scan(&row.ID, &row.NIK, &row.FullName, &row.Timestamp, &row.Direction); err != nil {
+			return nil, fmt.Errorf("gagal scan data :%w", err)
+		}
+		scans = append(scans, row)
+	}
+	return scans, rows.Err()
+}
+
+// GetNotesBetween: semua catatan dari tanggal `from` sampai `to` (inklusif),
+// nik kosong = semua karyawan
+func (repo *FingerLogRepository) GetNotesBetween(from, to time.Time, nik string) ([]model.NoteResponse, error) {
+	query := `SELECT nik, to_char(date::date, 'YYYY-MM-DD'), category, detail, COALESCE(updated_by, ''), updated_at,
+            (SELECT COUNT(*) FROM note_attachments a WHERE a.nik = detaillog.nik AND a.date = detaillog.date::date)
+        FROM detaillog
+        WHERE date::date BETWEEN $1::date AND $2::date AND ($3 = '' OR nik = $3)
+        ORDER BY date, nik`
+	rows, err := repo.DB.Query(query, from.Format("2006-01-02"), to.Format("2006-01-02"), nik)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []model.NoteResponse{}
+	for rows.Next() {
+		var n model.NoteResponse
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&n.NIK, &n.Date, &n.Category, &n.Note, &n.UpdatedBy, &updatedAt, &n.AttachmentCount); err != nil {
+			return nil, fmt.Errorf("gagal scan row notes: %w", err)
+		}
+		n.UpdatedAt = nullTimePtr(updatedAt)
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// StreamScans: memanggil fn untuk setiap scan di [start, end) langsung dari cursor,
+// diurutkan per departemen (penugasan pada tanggal scan), NIK lalu waktu.
+func (repo *FingerLogRepository) StreamScans(start, end time.Time, filter model.OrgFilter, fn func(department string, scan model.RawFingerLog) error) error {
+	args := []interface{}{start, end, sitetime.Name()}
+	scanDate := siteDate("f.timestamp", 3)
+	orgClause, args := orgFilterClause(filter, "u.nik", scanDate, args)
+	query := `SELECT COALESCE(d.name, ''), u.nik, u.full_name, f.timestamp, COALESCE(f.direction, '')
+        FROM fingerlog_clean f
+        JOIN users u ON f.nik = u.nik
+        ` + assignmentJoin("ua", "u.nik", scanDate) + `
+        LEFT JOIN departments d ON d.id = ua.department_id
+        WHERE f.timestamp >= $1 AND f.timestamp < $2` + orgClause + `
+        ORDER BY 1, u.nik, f.timestamp`
+
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("error executing query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var department string
+		var row model.RawFingerLog
+		if err := rows.Scan(&department, &row.NIK, &row.FullName, &row.Timestamp, &row.Direction); err != nil {
+			return fmt.Errorf("gagal scan data :%w", err)
+		}
+		if err := fn(department, row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrAttachmentNotFound = errors.New("lampiran tidak ditemukan")
+
+type NoteAttachmentRepository struct {
+	DB *sql.DB
+}
+
+func NewNoteAttachmentRepository(db *sql.DB) *NoteAttachmentRepository {
+	return &NoteAttachmentRepository{DB: db}
+}
+
+const attachmentColumns = `id, nik, to_char(date, 'YYYY-MM-DD'), filename, content_type, size_bytes, sha256,
+    storage_key, uploaded_by, created_at`
+
+func scanAttachment(row rowScanner) (model.NoteAttachment, error) {
+	var a model.NoteAttachment
+	err := row.Scan(&a.ID, &a.NIK, &a.Date, &a.Filename, &a.ContentType, &a.SizeBytes, &a.SHA256,
+		&a.StorageKey, &a.UploadedBy, &a.CreatedAt)
+	return a, err
+}
+
+// CreateAttachment: simpan metadata lampiran untuk catatan yang sudah ada.
+// Catatan dikunci agar tidak terhapus bersamaan dengan lampiran baru.
+func (repo *NoteAttachmentRepository) CreateAttachment(a model.NoteAttachment, audit model.AuditContext) (model.NoteAttachment, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRow(`SELECT 1 FROM detaillog WHERE nik = $1 AND date = $2 FOR SHARE`, a.NIK, a.Date).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return model.NoteAttachment{}, ErrNoteNotFound
+	}
+	if err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal membaca note: %w", err)
+	}
+
+	query := `INSERT INTO note_attachments (nik, date, filename, content_type, size_bytes, sha256, storage_key, uploaded_by)
+        VALUES ($1, $2::date, $3, $4, $5, $6, $7, $8)
+        RETURNING ` + attachmentColumns
+	created, err := scanAttachment(tx.QueryRow(query, a.NIK, a.Date, a.Filename, a.ContentType, a.SizeBytes,
+		a.SHA256, a.StorageKey, audit.Actor))
+	if err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal menyimpan lampiran: %w", err)
+	}
+	if err := writeAudit(tx, audit, model.AuditNoteAttach, created.NIK, created.Date, nil, created); err != nil {
+		return model.NoteAttachment{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal commit lampiran: %w", err)
+	}
+	return created, nil
+}
+
+func (repo *NoteAttachmentRepository) GetAttachment(id int) (model.NoteAttachment, error) {
+	a, err := scanAttachment(repo.DB.QueryRow(`SELECT `+attachmentColumns+` FROM note_attachments WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.NoteAttachment{}, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal mengambil lampiran: %w", err)
+	}
+	return a, nil
+}
+
+func (repo *NoteAttachmentRepository) ListAttachments(nik, date string) ([]model.NoteAttachment, error) {
+	rows, err := repo.DB.Query(`SELECT `+attachmentColumns+` FROM note_attachments
+        WHERE nik = $1 AND date = $2::date ORDER BY created_at, id`, nik, date)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query lampiran: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []model.NoteAttachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan lampiran: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// DeleteAttachment: hapus metadata satu lampiran; file di blob store dihapus pemanggil
+func (repo *NoteAttachmentRepository) DeleteAttachment(id int, audit model.AuditContext) (model.NoteAttachment, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := deleteAttachments(tx, audit, `id = $1`, id)
+	if err != nil {
+		return model.NoteAttachment{}, err
+	}
+	if len(deleted) == 0 {
+		return model.NoteAttachment{}, ErrAttachmentNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("gagal commit lampiran: %w", err)
+	}
+	return deleted[0], nil
+}
+
+// deleteAttachments: hapus metadata lampiran yang cocok dengan `where` dan catat
+// di audit. Dipakai juga saat catatan dihapus.
+func deleteAttachments(tx *sql.Tx, audit model.AuditContext, where string, args ...interface{}) ([]model.NoteAttachment, error) {
+	rows, err := tx.Query(`DELETE FROM note_attachments WHERE `+where+` RETURNING `+attachmentColumns, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghapus lampiran: %w", err)
+	}
+	var deleted []model.NoteAttachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("gagal membaca lampiran terhapus: %w", err)
+		}
+		deleted = append(deleted, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("gagal menghapus lampiran: %w", err)
+	}
+
+	for _, a := range deleted {
+		if err := writeAudit(tx, audit, model.AuditNoteDetach, a.NIK, a.Date, a, nil); err != nil {
+			return nil, err
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrAssignmentOverlap = errors.New("sudah ada penugasan yang dimulai pada atau setelah tanggal tsb")
+
+type OrganizationRepository struct {
+	DB *sql.DB
+}
+
+func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{DB: db}
+}
+
+func (repo *OrganizationRepository) CreateDepartment(data *model.CreateDepartmentRequest) (model.Department, error) {
+	dept := model.Department{Code: data.Code, Name: data.Name, Site: data.Site}
+	query := `INSERT INTO departments (code, name, site) VALUES ($1, $2, NULLIF($3, '')) RETURNING id, created_at`
+	err := repo.DB.QueryRow(query, data.Code, data.Name, data.Site).Scan(&dept.ID, &dept.CreatedAt)
+	if err != nil {
+		return model.Department{}, fmt.Errorf("gagal menambahkan departemen: %w", err)
+	}
+	return dept, nil
+}
+
+func (repo *OrganizationRepository) GetDepartments() ([]model.Department, error) {
+	query := `SELECT id, code, name, COALESCE(site, ''), created_at FROM departments ORDER BY code`
+	rows, err := repo.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query departemen: %w", err)
+	}
+	defer rows.Close()
+
+	departments := []model.Department{}
+	for rows.Next() {
+		var d model.Department
+		if err := rows.Scan(&d.ID, &d.Code, &d.Name, &d.Site, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal scan departemen: %w", err)
+		}
+		departments = append(departments, d)
+	}
+	return departments, rows.Err()
+}
+
+func (repo *OrganizationRepository) CreateLine(data *model.CreateLineRequest) (model.ProductionLine, error) {
+	line := model.ProductionLine{DepartmentID: data.DepartmentID, Code: data.Code, Name: data.Name}
+	query := `INSERT INTO production_lines (department_id, code, name) VALUES ($1, $2, $3) RETURNING id, created_at`
+	err := repo.DB.QueryRow(query, data.DepartmentID, data.Code, data.Name).Scan(&line.ID, &line.CreatedAt)
+	if err != nil {
+		return model.ProductionLine{}, fmt.Errorf("gagal menambahkan line: %w", err)
+	}
+	return line, nil
+}
+
+// GetLines: departmentID 0 berarti semua line
+func (repo *OrganizationRepository) GetLines(departmentID int) ([]model.ProductionLine, error) {
+	query := `SELECT id, department_id, code, name, created_at FROM production_lines
+        WHERE ($1 = 0 OR department_id = $1)
+        ORDER BY code`
+	rows, err := repo.DB.Query(query, departmentID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query line: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []model.ProductionLine{}
+	for rows.Next() {
+		var l model.ProductionLine
+		if err := rows.Scan(&l.ID, &l.DepartmentID, &l.Code, &l.Name, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("gagal scan line: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+// AssignUser: menutup penugasan yang masih terbuka sehari sebelum effectiveFrom,
+// lalu menyimpan penugasan baru. Keduanya dalam satu transaksi. Penugasan yang
+// dimulai di dalam rentang baru menghasilkan ErrAssignmentOverlap.
+func (repo *OrganizationRepository) AssignUser(nik string, data *model.AssignUserRequest, effectiveFrom time.Time, effectiveTo *time.Time) (model.UserAssignment, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.UserAssignment{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	var overlap bool
+	overlapQuery := `SELECT EXISTS(SELECT 1 FROM user_assignments
+        WHERE nik = $1 AND effective_from >= $2 AND ($3::date IS NULL OR effective_from <= $3))`
+	if err := tx.QueryRow(overlapQuery, nik, effectiveFrom, effectiveTo).Scan(&overlap); err != nil {
+		return model.UserAssignment{}, fmt.Errorf("gagal memeriksa penugasan: %w", err)
+	}
+	if overlap {
+		return model.UserAssignment{}, ErrAssignmentOverlap
+	}
+
+	closeQuery := `UPDATE user_assignments
+        SET effective_to = $2::date - 1
+        WHERE nik = $1 AND effective_from < $2 AND (effective_to IS NULL OR effective_to >= $2)`
+	if _, err := tx.Exec(closeQuery, nik, effectiveFrom); err != nil {
+		return model.UserAssignment{}, fmt.Errorf("gagal menutup penugasan lama: %w", err)
+	}
+
+	var supervisor sql.NullString
+	if data.SupervisorNIK != "" {
+		supervisor = sql.NullString{String: data.SupervisorNIK, Valid: true}
+	}
+
+	assignment := model.UserAssignment{
+		NIK:           nik,
+		DepartmentID:  data.DepartmentID,
+		LineID:        data.LineID,
+		SupervisorNIK: data.SupervisorNIK,
+		EffectiveFrom: effectiveFrom,
+		EffectiveTo:   effectiveTo,
+	}
+	insertQuery := `INSERT INTO user_assignments (nik, department_id, line_id, supervisor_nik, effective_from, effective_to)
+        VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err = tx.QueryRow(insertQuery, nik, data.DepartmentID, data.LineID, supervisor, effectiveFrom, effectiveTo).Scan(&assignment.ID)
+	if err != nil {
+		return model.UserAssignment{}, fmt.Errorf("gagal menyimpan penugasan: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.UserAssignment{}, fmt.Errorf("gagal commit penugasan: %w", err)
+	}
+	return assignment, nil
+}
+
+func (repo *OrganizationRepository) GetAssignments(nik string) ([]model.UserAssignment, error) {
+	query := `SELECT id, nik, department_id, line_id, COALESCE(supervisor_nik, ''), effective_from, effective_to
+        FROM user_assignments WHERE nik = $1
+        ORDER BY effective_from DESC`
+	rows, err := repo.DB.Query(query, nik)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query penugasan: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []model.UserAssignment{}
+	for rows.Next() {
+		var a model.UserAssignment
+		var lineID sql.NullInt64
+		var effectiveTo sql.NullTime
+		if err := rows.Scan(&a.ID, &a.NIK, &a.DepartmentID, &lineID, &a.SupervisorNIK, &a.EffectiveFrom, &effectiveTo); err != nil {
+			return nil, fmt.Errorf("gagal scan penugasan: %w", err)
+		}
+		if lineID.Valid {
+			id := int(lineID.Int64)
+			a.LineID = &id
+		}
+		a.EffectiveTo = nullTimePtr(effectiveTo)
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
+
+// SupervisorOf: atasan langsung karyawan menurut penugasan yang berlaku pada tanggal tsb
+func (repo *OrganizationRepository) SupervisorOf(nik string, date time.Time) (string, error) {
+	var supervisor string
+	query := `SELECT COALESCE(supervisor_nik, '') FROM user_assignments
+        WHERE nik = $1 AND effective_from <= $2 AND (effective_to IS NULL OR effective_to >= $2)
+        ORDER BY effective_from DESC LIMIT 1`
+	err := repo.DB.QueryRow(query, nik, date).Scan(&supervisor)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("gagal mengambil atasan: %w", err)
+	}
+	return supervisor, nil
+}
+
+// assignmentJoin: LEFT JOIN LATERAL ke satu penugasan (department_id, line_id,
+// supervisor_nik) yang berlaku pada dateExpr. Jika data lama memiliki penugasan
+// yang beririsan dipilih yang mulai paling akhir, sehingga baris tidak berlipat.
+func assignmentJoin(alias, nikExpr, dateExpr string) string {
+	return fmt.Sprintf(`LEFT JOIN LATERAL (
+            SELECT department_id, line_id, supervisor_nik FROM user_assignments
+            WHERE nik = %s AND effective_from <= %s AND (effective_to IS NULL OR effective_to >= %s)
+            ORDER BY effective_from DESC, id DESC LIMIT 1
+        ) %s ON TRUE`, nikExpr, dateExpr, dateExpr, alias)
+}
+
+// orgFilterClause: membangun kondisi tambahan (diawali " AND") untuk membatasi
+// user berdasarkan penugasan yang berlaku pada dateExpr. nikExpr adalah kolom NIK
+// di query utama. args yang sudah ada diperpanjang dengan parameter filter.
+func orgFilterClause(filter model.OrgFilter, nikExpr, dateExpr string, args []interface{}) (string, []interface{}) {
+	if filter.IsEmpty() {
+		return "", args
+	}
+
+	clause := fmt.Sprintf(` AND EXISTS (
+        SELECT 1 FROM user_assignments ua
+        WHERE ua.nik = %s
+          AND ua.effective_from <= %s
+          AND (ua.effective_to IS NULL OR ua.effective_to >= %s)`, nikExpr, dateExpr, dateExpr)
+
+	if filter.DepartmentID != 0 {
+		args = append(args, filter.DepartmentID)
+		clause += fmt.Sprintf(" AND ua.department_id = $%d", len(args))
+	}
+	if filter.LineID != 0 {
+		args = append(args, filter.LineID)
+		clause += fmt.Sprintf(" AND ua.line_id = $%d", len(args))
+	}
+	if filter.SupervisorNIK != "" {
+		args = append(args, filter.SupervisorNIK)
+		clause += fmt.Sprintf(" AND ua.supervisor_nik = $%d", len(args))
+	}
+	clause += ")"
+
+	return clause, args
+}
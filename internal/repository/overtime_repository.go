@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRequestNotFound   = errors.New("pengajuan tidak ditemukan")
+	ErrRequestNotPending = errors.New("pengajuan sudah diputuskan")
+)
+
+type OvertimeRepository struct {
+	DB *sql.DB
+}
+
+func NewOvertimeRepository(db *sql.DB) *OvertimeRepository {
+	return &OvertimeRepository{DB: db}
+}
+
+const overtimeColumns = `id, nik, date, planned_minutes, reason, status, requested_by,
+    COALESCE(decided_by, ''), decided_at, COALESCE(decision_note, ''), created_at`
+
+func scanOvertimeRequest(row rowScanner) (model.OvertimeRequest, error) {
+	var r model.OvertimeRequest
+	var decidedAt sql.NullTime
+	err := row.Scan(&r.ID, &r.NIK, &r.Date, &r.PlannedMinutes, &r.Reason, &r.Status, &r.RequestedBy,
+		&r.DecidedBy, &decidedAt, &r.DecisionNote, &r.CreatedAt)
+	r.DecidedAt = nullTimePtr(decidedAt)
+	return r, err
+}
+
+func (repo *OvertimeRepository) CreateRequest(data *model.CreateOvertimeRequest, date time.Time) (model.OvertimeRequest, error) {
+	query := `INSERT INTO overtime_requests (nik, date, planned_minutes, reason, requested_by)
+        VALUES ($1, $2, $3, $4, $5) RETURNING ` + overtimeColumns
+	request, err := scanOvertimeRequest(repo.DB.QueryRow(query, data.NIK, date, data.PlannedMinutes, data.Reason, data.RequestedBy))
+	if err != nil {
+		return model.OvertimeRequest{}, fmt.Errorf("gagal menyimpan pengajuan lembur: %w", err)
+	}
+	return request, nil
+}
+
+func (repo *OvertimeRepository) GetRequest(id int) (model.OvertimeRequest, error) {
+	query := `SELECT ` + overtimeColumns + ` FROM overtime_requests WHERE id = $1`
+	request, err := scanOvertimeRequest(repo.DB.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return model.OvertimeRequest{}, ErrRequestNotFound
+	}
+	if err != nil {
+		return model.OvertimeRequest{}, fmt.Errorf("gagal mengambil pengajuan lembur: %w", err)
+	}
+	return request, nil
+}
+
+// HasActiveRequest: sudah ada pengajuan yang belum ditolak untuk nik dan tanggal tsb
+func (repo *OvertimeRepository) HasActiveRequest(nik string, date time.Time) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM overtime_requests WHERE nik = $1 AND date = $2 AND status <> $3)`
+	if err := repo.DB.QueryRow(query, nik, date, model.RequestRejected).Scan(&exists); err != nil {
+		return false, fmt.Errorf("gagal memeriksa pengajuan lembur: %w", err)
+	}
+	return exists, nil
+}
+
+func (repo *OvertimeRepository) ListRequests(filter model.OvertimeRequestFilter) ([]model.OvertimeRequest, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.Date != "" {
+		args = append(args, filter.Date)
+		conditions = append(conditions, fmt.Sprintf("date = $%d", len(args)))
+	}
+	if filter.NIK != "" {
+		args = append(args, filter.NIK)
+		conditions = append(conditions, fmt.Sprintf("nik = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT ` + overtimeColumns + ` FROM overtime_requests
+        WHERE ` + strings.Join(conditions, " AND ") + `
+        ORDER BY date DESC, nik`
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query pengajuan lembur: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []model.OvertimeRequest{}
+	for rows.Next() {
+		r, err := scanOvertimeRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan pengajuan lembur: %w", err)
+		}
+		requests = append(requests, r)
+	}
+	return requests, rows.Err()
+}
+
+// Decide: menyetujui/menolak pengajuan yang masih berstatus submitted
+func (repo *OvertimeRepository) Decide(id int, status string, data *model.DecideRequest) (model.OvertimeRequest, error) {
+	query := `UPDATE overtime_requests
+        SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = NULLIF($4, '')
+        WHERE id = $1 AND status = $5
+        RETURNING ` + overtimeColumns
+	request, err := scanOvertimeRequest(repo.DB.QueryRow(query, id, status, data.DecidedBy, data.Note, model.RequestSubmitted))
+	if err == sql.ErrNoRows {
+		// Bedakan antara id tidak ada dan sudah diputuskan
+		if _, getErr := repo.GetRequest(id); getErr != nil {
+			return model.OvertimeRequest{}, getErr
+		}
+		return model.OvertimeRequest{}, ErrRequestNotPending
+	}
+	if err != nil {
+		return model.OvertimeRequest{}, fmt.Errorf("gagal memperbarui pengajuan lembur: %w", err)
+	}
+	return request, nil
+}
+
+// ApprovedMinutes: menit lembur yang disetujui per NIK + tanggal pada rentang [from, to]
+func (repo *OvertimeRepository) ApprovedMinutes(from, to time.Time) (map[string]int, error) {
+	query := `SELECT nik, to_char(date, 'YYYY-MM-DD'), planned_minutes
+        FROM overtime_requests
+        WHERE status = $1 AND date BETWEEN $2 AND $3`
+	rows, err := repo.DB.Query(query, model.RequestApproved, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query lembur disetujui: %w", err)
+	}
+	defer rows.Close()
+
+	approved := make(map[string]int)
+	for rows.Next() {
+		var nik, date string
+		var minutes int
+		if err := rows.Scan(&nik, &date, &minutes); err != nil {
+			return nil, fmt.Errorf("gagal scan lembur disetujui: %w", err)
+		}
+		approved[nik+"|"+date] = minutes
+	}
+	return approved, rows.Err()
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrTemplateNotFound = errors.New("template payroll tidak ditemukan")
+
+type PayrollRepository struct {
+	DB *sql.DB
+}
+
+func NewPayrollRepository(db *sql.DB) *PayrollRepository {
+	return &PayrollRepository{DB: db}
+}
+
+func scanTemplate(row rowScanner) (model.PayrollTemplate, error) {
+	var t model.PayrollTemplate
+	var definition string
+	var updatedAt sql.NullTime
+	if err := row.Scan(&definition, &updatedAt); err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal([]byte(definition), &t); err != nil {
+		return t, fmt.Errorf("definisi template rusak: %w", err)
+	}
+	t.UpdatedAt = nullTimePtr(updatedAt)
+	return t, nil
+}
+
+// SaveTemplate: simpan atau ganti template dengan nama t.Name
+func (repo *PayrollRepository) SaveTemplate(t model.PayrollTemplate) (model.PayrollTemplate, error) {
+	t.UpdatedAt = nil
+	definition, err := json.Marshal(t)
+	if err != nil {
+		return model.PayrollTemplate{}, fmt.Errorf("gagal menyimpan template: %w", err)
+	}
+	query := `INSERT INTO payroll_templates (name, definition, updated_at) VALUES ($1, $2::jsonb, NOW())
+        ON CONFLICT (name) DO UPDATE SET definition = EXCLUDED.definition, updated_at = EXCLUDED.updated_at
+        RETURNING definition::text, updated_at`
+	saved, err := scanTemplate(repo.DB.QueryRow(query, t.Name, string(definition)))
+	if err != nil {
+		return model.PayrollTemplate{}, fmt.Errorf("gagal menyimpan template: %w", err)
+	}
+	return saved, nil
+}
+
+func (repo *PayrollRepository) GetTemplate(name string) (model.PayrollTemplate, error) {
+	t, err := scanTemplate(repo.DB.QueryRow(`SELECT definition::text, updated_at FROM payroll_templates WHERE name = $1`, name))
+	if err == sql.ErrNoRows {
+		return model.PayrollTemplate{}, ErrTemplateNotFound
+	}
+	if err != nil {
+		return model.PayrollTemplate{}, fmt.Errorf("gagal mengambil template: %w", err)
+	}
+	return t, nil
+}
+
+func (repo *PayrollRepository) ListTemplates() ([]model.PayrollTemplate, error) {
+	rows, err := repo.DB.Query(`SELECT definition::text, updated_at FROM payroll_templates ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query template: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []model.PayrollTemplate{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("gagal scan template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// DeleteTemplate: pemetaan ID karyawan ikut terhapus (ON DELETE CASCADE)
+func (repo *PayrollRepository) DeleteTemplate(name string) error {
+	result, err := repo.DB.Exec(`DELETE FROM payroll_templates WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("gagal menghapus template: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// SaveEmployeeIDs: upsert pemetaan NIK -> ID payroll; external_id kosong menghapus
+func (repo *PayrollRepository) SaveEmployeeIDs(template string, ids []model.PayrollEmployeeID) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		nik, externalID := strings.TrimSpace(id.NIK), strings.TrimSpace(id.ExternalID)
+		if externalID == "" {
+			_, err = tx.Exec(`DELETE FROM payroll_employee_ids WHERE template = $1 AND nik = $2`, template, nik)
+		} else {
+			_, err = tx.Exec(`INSERT INTO payroll_employee_ids (template, nik, external_id) VALUES ($1, $2, $3)
+                ON CONFLICT (template, nik) DO UPDATE SET external_id = EXCLUDED.external_id`, template, nik, externalID)
+		}
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan ID payroll %s: %w", nik, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// EmployeeIDs: NIK -> ID payroll untuk template
+func (repo *PayrollRepository) EmployeeIDs(template string) (map[string]string, error) {
+	rows, err := repo.DB.Query(`SELECT nik, external_id FROM payroll_employee_ids WHERE template = $1`, template)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query ID payroll: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var nik, externalID string
+		if err := rows.Scan(&nik, &externalID); err != nil {
+			return nil, fmt.Errorf("gagal scan ID payroll: %w", err)
+		}
+		ids[nik] = externalID
+	}
+	return ids, rows.Err()
+}
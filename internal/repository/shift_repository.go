@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"Steril-App/model"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrUnknownShiftCode = errors.New("kode shift tidak dikenal")
+
+type ShiftRepository struct {
+	DB *sql.DB
+}
+
+func NewShiftRepository(db *sql.DB) *ShiftRepository {
+	return &ShiftRepository{DB: db}
+}
+
+func (repo *ShiftRepository) CreateShift(data *model.CreateShiftRequest) (model.Shift, error) {
+	shift := model.Shift{
+		Code:                data.Code,
+		Name:                data.Name,
+		StartTime:           data.StartTime,
+		EndTime:             data.EndTime,
+		GraceMinutes:        data.GraceMinutes,
+		BreakMinutes:        data.BreakMinutes,
+		WindowBeforeMinutes: *data.WindowBeforeMinutes,
+		WindowAfterMinutes:  *data.WindowAfterMinutes,
+	}
+	query := `INSERT INTO shifts (code, name, start_time, end_time, grace_minutes, break_minutes,
+            window_before_minutes, window_after_minutes)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	err := repo.DB.QueryRow(query, data.Code, data.Name, data.StartTime, data.EndTime, data.GraceMinutes, data.BreakMinutes,
+		shift.WindowBeforeMinutes, shift.WindowAfterMinutes).Scan(&shift.ID)
+	if err != nil {
+		return model.Shift{}, fmt.Errorf("gagal menambahkan shift: %w", err)
+	}
+	return shift, nil
+}
+
+func (repo *ShiftRepository) GetShifts() ([]model.Shift, error) {
+	query := `SELECT id, code, name, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+            grace_minutes, break_minutes, window_before_minutes, window_after_minutes
+        FROM shifts ORDER BY start_time`
+	rows, err := repo.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query shift: %w", err)
+	}
+	defer rows.Close()
+
+	shifts := []model.Shift{}
+	for rows.Next() {
+		var s model.Shift
+		if err := rows.Scan(&s.ID, &s.Code, &s.Name, &s.StartTime, &s.EndTime, &s.GraceMinutes, &s.BreakMinutes,
+			&s.WindowBeforeMinutes, &s.WindowAfterMinutes); err != nil {
+			return nil, fmt.Errorf("gagal scan shift: %w", err)
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, rows.Err()
+}
+
+// CreatePattern: menyimpan pola beserta urutan harinya. Kode shift diterjemahkan ke ID.
+func (repo *ShiftRepository) CreatePattern(data *model.CreatePatternRequest) (model.SchedulePattern, error) {
+	shifts, err := repo.GetShifts()
+	if err != nil {
+		return model.SchedulePattern{}, err
+	}
+	byCode := make(map[string]model.Shift)
+	for _, s := range shifts {
+		byCode[s.Code] = s
+	}
+
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return model.SchedulePattern{}, fmt.Errorf("gagal memulai transaksi: %w", err)
+	}
+	defer tx.Rollback()
+
+	pattern := model.SchedulePattern{Code: data.Code, Name: data.Name}
+	err = tx.QueryRow(`INSERT INTO schedule_patterns (code, name) VALUES ($1, $2) RETURNING id`, data.Code, data.Name).Scan(&pattern.ID)
+	if err != nil {
+		return model.SchedulePattern{}, fmt.Errorf("gagal menambahkan pola jadwal: %w", err)
+	}
+
+	for i, code := range data.Days {
+		day := model.PatternDay{DayIndex: i}
+		code = strings.TrimSpace(code)
+		if code != "" && !strings.EqualFold(code, "OFF") {
+			shift, ok := byCode[code]
+			if !ok {
+				return model.SchedulePattern{}, fmt.Errorf("%w: %s", ErrUnknownShiftCode, code)
+			}
+			id := shift.ID
+			day.ShiftID = &id
+			day.ShiftCode = shift.Code
+		}
+
+		_, err := tx.Exec(`INSERT INTO schedule_pattern_days (pattern_id, day_index, shift_id) VALUES ($1, $2, $3)`,
+			pattern.ID, day.DayIndex, day.ShiftID)
+		if err != nil {
+			return model.SchedulePattern{}, fmt.Errorf("gagal menyimpan hari pola: %w", err)
+		}
+		pattern.Days = append(pattern.Days, day)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.SchedulePattern{}, fmt.Errorf("gagal commit pola jadwal: %w", err)
+	}
+	return pattern, nil
+}
+
+func (repo *ShiftRepository) GetPatterns() ([]model.SchedulePattern, error) {
+	query := `SELECT p.id, p.code, p.name, d.day_index, d.shift_id, COALESCE(s.code, '')
+        FROM schedule_patterns p
+        JOIN schedule_pattern_days d ON d.pattern_id = p.id
+        LEFT JOIN shifts s ON s.id = d.shift_id
+        ORDER BY p.code, d.day_index`
+	rows, err := repo.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query pola jadwal: %w", err)
+	}
+	defer rows.Close()
+
+	patterns := []model.SchedulePattern{}
+	indices := make(map[int]int)
+	for rows.Next() {
+		var p model.SchedulePattern
+		var day model.PatternDay
+		var shiftID sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.Code, &p.Name, &day.DayIndex, &shiftID, &day.ShiftCode); err != nil {
+			return nil, fmt.Errorf("gagal scan pola jadwal: %w", err)
+		}
+		if shiftID.Valid {
+			id := int(shiftID.Int64)
+			day.ShiftID = &id
+		}
+
+		idx, exists := indices[p.ID]
+		if !exists {
+			patterns = append(patterns, p)
+			idx = len(patterns) - 1
+			indices[p.ID] = idx
+		}
+		patterns[idx].Days = append(patterns[idx].Days, day)
+	}
+	return patterns, rows.Err()
+}
+
+func (repo *ShiftRepository) AssignSchedule(data *model.AssignScheduleRequest, startDate time.Time, endDate *time.Time) (model.ScheduleAssignment, error) {
+	var nik sql.NullString
+	if data.NIK != "" {
+		nik = sql.NullString{String: data.NIK, Valid: true}
+	}
+
+	assignment := model.ScheduleAssignment{
+		PatternID: data.PatternID,
+		NIK:       data.NIK,
+		LineID:    data.LineID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	query := `INSERT INTO schedule_assignments (pattern_id, nik, line_id, start_date, end_date)
+        VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := repo.DB.QueryRow(query, data.PatternID, nik, data.LineID, startDate, endDate).Scan(&assignment.ID)
+	if err != nil {
+		return model.ScheduleAssignment{}, fmt.Errorf("gagal menyimpan jadwal: %w", err)
+	}
+	return assignment, nil
+}
+
+// LoadScheduleData: mengambil semua data jadwal yang berlaku di rentang [from, to]
+func (repo *ShiftRepository) LoadScheduleData(from, to time.Time) (model.ScheduleData, error) {
+	data := model.ScheduleData{
+		Shifts:   make(map[int]model.Shift),
+		Patterns: make(map[int]model.SchedulePattern),
+	}
+
+	shifts, err := repo.GetShifts()
+	if err != nil {
+		return data, err
+	}
+	for _, s := range shifts {
+		data.Shifts[s.ID] = s
+	}
+
+	patterns, err := repo.GetPatterns()
+	if err != nil {
+		return data, err
+	}
+	for _, p := range patterns {
+		data.Patterns[p.ID] = p
+	}
+
+	rows, err := repo.DB.Query(`SELECT id, pattern_id, COALESCE(nik, ''), line_id, start_date, end_date
+        FROM schedule_assignments
+        WHERE start_date <= $2 AND (end_date IS NULL OR end_date >= $1)
+        ORDER BY start_date DESC, id DESC`, from, to)
+	if err != nil {
+		return data, fmt.Errorf("gagal query jadwal: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a model.ScheduleAssignment
+		var lineID sql.NullInt64
+		var endDate sql.NullTime
+		if err := rows.Scan(&a.ID, &a.PatternID, &a.NIK, &lineID, &a.StartDate, &endDate); err != nil {
+			return data, fmt.Errorf("gagal scan jadwal: %w", err)
+		}
+		if lineID.Valid {
+			id := int(lineID.Int64)
+			a.LineID = &id
+		}
+		a.EndDate = nullTimePtr(endDate)
+		data.Assignments = append(data.Assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return data, err
+	}
+
+	userRows, err := repo.DB.Query(`SELECT id, nik, department_id, line_id, COALESCE(supervisor_nik, ''), effective_from, effective_to
+        FROM user_assignments
+        WHERE effective_from <= $2 AND (effective_to IS NULL OR effective_to >= $1)`, from, to)
+	if err != nil {
+		return data, fmt.Errorf("gagal query penugasan user: %w", err)
+	}
+	defer userRows.Close()
+	for userRows.Next() {
+		var a model.UserAssignment
+		var lineID sql.NullInt64
+		var effectiveTo sql.NullTime
+		if err := userRows.Scan(&a.ID, &a.NIK, &a.DepartmentID, &lineID, &a.SupervisorNIK, &a.EffectiveFrom, &effectiveTo); err != nil {
+			return data, fmt.Errorf("gagal scan penugasan user: %w", err)
+		}
+		if lineID.Valid {
+			id := int(lineID.Int64)
+			a.LineID = &id
+		}
+		a.EffectiveTo = nullTimePtr(effectiveTo)
+		data.UserAssignments = append(data.UserAssignments, a)
+	}
+	return data, userRows.Err()
+}
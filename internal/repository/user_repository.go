@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"Steril-App/internal/sitetime"
 	"Steril-App/model"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 )
 
+var ErrUserNotFound = errors.New("user tidak ditemukan")
+
 type UserRepository struct {
 	DB *sql.DB
 }
@@ -16,9 +21,11 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 func (repo *UserRepository) CreateUser(data *model.CreateUserRequest) error {
-	query := "INSERT INTO users (nik, full_name) VALUES ($1, $2)"
+	query := `INSERT INTO users (nik, full_name, employment_type, job_title, start_date, end_date, contract_end_date)
+        VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, '')::date, NULLIF($6, '')::date, NULLIF($7, '')::date)`
 	fmt.Println(data.NIK)
-	result, err := repo.DB.Exec(query, data.NIK, data.FullName)
+	result, err := repo.DB.Exec(query, data.NIK, data.FullName, data.EmploymentType, data.JobTitle,
+		data.StartDate, data.EndDate, data.ContractEndDate)
 	if err != nil {
 		// Log error SQL di sini.
 		log.Printf("ERROR SQL: Gagal insert user %s: %v", data.NIK, err)
@@ -72,18 +79,34 @@ func (repo *UserRepository) DeleteUser(id string) error {
 	return nil
 }
 
-func (repo *UserRepository) GetAllUser() ([]model.UserResponse, error) {
-	query := `SELECT id, nik, full_name FROM users`
-	result, err := repo.DB.Query(query)
+// GetAllUser: daftar user beserta penugasan yang berlaku hari ini, bisa difilter per departemen/line/atasan
+func (repo *UserRepository) GetAllUser(filter model.OrgFilter) ([]model.UserResponse, error) {
+	// "Hari ini" mengikuti zona site, bukan CURRENT_DATE session Postgres
+	args := []interface{}{sitetime.Today().Format("2006-01-02")}
+	orgClause, args := orgFilterClause(filter, "u.nik", "$1::date", args)
+	query := `SELECT u.id, u.nik, u.full_name,
+            COALESCE(u.employment_type, ''), COALESCE(u.job_title, ''),
+            u.start_date, u.end_date, u.contract_end_date,
+            COALESCE(d.name, ''), COALESCE(l.name, ''), COALESCE(ua.supervisor_nik, '')
+        FROM users u
+        ` + assignmentJoin("ua", "u.nik", "$1::date") + `
+        LEFT JOIN departments d ON d.id = ua.department_id
+        LEFT JOIN production_lines l ON l.id = ua.line_id
+        WHERE TRUE` + orgClause + `
+        ORDER BY u.nik`
+	result, err := repo.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("gagal mendapatkan data dari database :%w", err)
 	}
+	defer result.Close()
 
 	var rows []model.UserResponse
 
 	for result.Next() {
-		var row = model.UserResponse{}
-		result.Scan(&row.ID, &row.NIK, &row.FullName)
+		row, err := scanUserResponse(result)
+		if err != nil {
+			return nil, err
+		}
 
 		rows = append(rows, row)
 	}
@@ -91,3 +114,132 @@ func (repo *UserRepository) GetAllUser() ([]model.UserResponse, error) {
 	return rows, nil
 
 }
+
+func (repo *UserRepository) UpdateEmployment(nik string, data *model.EmploymentData) error {
+	query := `UPDATE users SET
+            employment_type = NULLIF($2, ''),
+            job_title = NULLIF($3, ''),
+            start_date = NULLIF($4, '')::date,
+            end_date = NULLIF($5, '')::date,
+            contract_end_date = NULLIF($6, '')::date
+        WHERE nik = $1`
+	result, err := repo.DB.Exec(query, nik, data.EmploymentType, data.JobTitle, data.StartDate, data.EndDate, data.ContractEndDate)
+	if err != nil {
+		return fmt.Errorf("gagal memperbarui data kepegawaian: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("gagal mendapatkan RowsAffected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// GetExpiringContracts: user yang kontraknya berakhir antara hari ini dan `days` hari ke depan
+func (repo *UserRepository) GetExpiringContracts(days int) ([]model.UserResponse, error) {
+	query := `SELECT u.id, u.nik, u.full_name,
+            COALESCE(u.employment_type, ''), COALESCE(u.job_title, ''),
+            u.start_date, u.end_date, u.contract_end_date,
+            COALESCE(d.name, ''), COALESCE(l.name, ''), COALESCE(ua.supervisor_nik, '')
+        FROM users u
+        ` + assignmentJoin("ua", "u.nik", "$2::date") + `
+        LEFT JOIN departments d ON d.id = ua.department_id
+        LEFT JOIN production_lines l ON l.id = ua.line_id
+        WHERE u.contract_end_date BETWEEN $2::date AND $2::date + $1::int
+          AND (u.end_date IS NULL OR u.end_date >= $2::date)
+        ORDER BY u.contract_end_date, u.nik`
+	result, err := repo.DB.Query(query, days, sitetime.Today().Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal query kontrak yang akan berakhir: %w", err)
+	}
+	defer result.Close()
+
+	rows := []model.UserResponse{}
+	for result.Next() {
+		row, err := scanUserResponse(result)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, result.Err()
+}
+
+func scanUserResponse(rows *sql.Rows) (model.UserResponse, error) {
+	var row model.UserResponse
+	var startDate, endDate, contractEndDate sql.NullTime
+	err := rows.Scan(&row.ID, &row.NIK, &row.FullName, &row.EmploymentType, &row.JobTitle,
+		&startDate, &endDate, &contractEndDate,
+		&row.DepartmentName, &row.LineName, &row.SupervisorNIK)
+	if err != nil {
+		return model.UserResponse{}, fmt.Errorf("gagal scan data user :%w", err)
+	}
+	row.StartDate = nullTimePtr(startDate)
+	row.EndDate = nullTimePtr(endDate)
+	row.ContractEndDate = nullTimePtr(contractEndDate)
+	return row, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// employeeQuery: karyawan beserta departemen menurut penugasan yang berlaku pada $2
+var employeeQuery = `SELECT u.nik, u.full_name, COALESCE(d.name, ''), COALESCE(d.id, 0), COALESCE(d.site, ''),
+            u.start_date, u.end_date
+        FROM users u
+        ` + assignmentJoin("ua", "u.nik", "$2::date") + `
+        LEFT JOIN departments d ON d.id = ua.department_id`
+
+// GetActiveEmployees: karyawan yang masa kerjanya beririsan dengan [from, to].
+// Filter organisasi dicocokkan dengan penugasan yang berlaku pada tanggal `to`.
+func (repo *UserRepository) GetActiveEmployees(from, to time.Time, filter model.OrgFilter) ([]model.Employee, error) {
+	args := []interface{}{from, to}
+	orgClause, args := orgFilterClause(filter, "u.nik", "$2::date", args)
+	query := employeeQuery + `
+        WHERE (u.start_date IS NULL OR u.start_date <= $2::date)
+          AND (u.end_date IS NULL OR u.end_date >= $1::date)` + orgClause + `
+        ORDER BY u.nik`
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("gagal query karyawan aktif: %w", err)
+	}
+	defer rows.Close()
+
+	employees := []model.Employee{}
+	for rows.Next() {
+		e, err := scanEmployee(rows)
+		if err != nil {
+			return nil, err
+		}
+		employees = append(employees, e)
+	}
+	return employees, rows.Err()
+}
+
+// GetEmployee: satu karyawan dengan departemen yang berlaku pada tanggal `date`
+func (repo *UserRepository) GetEmployee(nik string, date time.Time) (model.Employee, error) {
+	query := employeeQuery + ` WHERE u.nik = $1`
+	e, err := scanEmployee(repo.DB.QueryRow(query, nik, date))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Employee{}, ErrUserNotFound
+	}
+	return e, err
+}
+
+func scanEmployee(row rowScanner) (model.Employee, error) {
+	var e model.Employee
+	var startDate, endDate sql.NullTime
+	if err := row.Scan(&e.NIK, &e.FullName, &e.DepartmentName, &e.DepartmentID, &e.Site, &startDate, &endDate); err != nil {
+		return model.Employee{}, fmt.Errorf("gagal scan karyawan: %w", err)
+	}
+	e.StartDate = nullTimePtr(startDate)
+	e.EndDate = nullTimePtr(endDate)
+	return e, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// AbsenceService: mendeteksi karyawan terjadwal yang tidak pernah scan setelah
+// shift-nya berakhir, mencatatnya dan (opsional) mengirim webhook ke atasan line.
+// Tabel absences hanya snapshot saat deteksi dan tidak direkonsiliasi ulang;
+// cuti, catatan atau koreksi yang masuk belakangan hanya tercermin di laporan
+// kehadiran (summarizePeriod).
+type AbsenceService struct {
+	Repo       *repository.AbsenceRepository
+	OrgRepo    *repository.OrganizationRepository
+	Attendance *AttendanceService
+	WebhookURL string        // ABSENCE_WEBHOOK_URL, kosong = tanpa notifikasi
+	Interval   time.Duration // ABSENCE_CHECK_MINUTES, 0 = job tidak dijalankan
+	Grace      time.Duration // ABSENCE_GRACE_MINUTES setelah shift berakhir
+	client     *http.Client
+}
+
+func NewAbsenceService(repo *repository.AbsenceRepository, orgRepo *repository.OrganizationRepository, attendance *AttendanceService) *AbsenceService {
+	return &AbsenceService{
+		Repo:       repo,
+		OrgRepo:    orgRepo,
+		Attendance: attendance,
+		WebhookURL: os.Getenv("ABSENCE_WEBHOOK_URL"),
+		Interval:   time.Duration(envInt("ABSENCE_CHECK_MINUTES", 5)) * time.Minute,
+		Grace:      time.Duration(envInt("ABSENCE_GRACE_MINUTES", 30)) * time.Minute,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func envInt(key string, fallback int) int {
+	minutes, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || minutes < 0 {
+		return fallback
+	}
+	return minutes
+}
+
+// Run: menjalankan Detect setiap Interval sampai ctx selesai
+func (s *AbsenceService) Run(ctx context.Context) {
+	if s.Interval <= 0 {
+		log.Println("Deteksi ketidakhadiran otomatis dimatikan (ABSENCE_CHECK_MINUTES=0)")
+		return
+	}
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			absences, err := s.Detect(now)
+			if err != nil {
+				log.Printf("Deteksi ketidakhadiran gagal: %v", err)
+				continue
+			}
+			if len(absences) > 0 {
+				log.Printf("Tercatat %d ketidakhadiran baru", len(absences))
+			}
+			s.resendPending(now)
+		}
+	}
+}
+
+// Detect: memeriksa kemarin dan hari ini (shift malam kemarin baru berakhir hari ini).
+// Hanya shift yang sudah berakhir lebih dari Grace yang diperiksa. Mengembalikan
+// ketidakhadiran yang baru tercatat.
+func (s *AbsenceService) Detect(now time.Time) ([]model.Absence, error) {
+	today := sitetime.StartOfDay(now)
+	yesterday := today.AddDate(0, 0, -1)
+
+	_, perEmployee, err := s.Attendance.summarizePeriod(yesterday, today, model.OrgFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []model.Absence{}
+	for _, days := range perEmployee {
+		for _, day := range days {
+			if day.Status != model.StatusAbsent || day.ShiftEnd == nil || day.ShiftEnd.Add(s.Grace).After(now) {
+				continue
+			}
+			date, _ := sitetime.ParseDate(day.Date)
+			supervisor, err := s.OrgRepo.SupervisorOf(day.NIK, date)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, model.Absence{
+				NIK:            day.NIK,
+				FullName:       day.FullName,
+				DepartmentName: day.DepartmentName,
+				Date:           date,
+				ShiftCode:      day.ShiftCode,
+				ShiftStart:     *day.ShiftStart,
+				ShiftEnd:       *day.ShiftEnd,
+				SupervisorNIK:  supervisor,
+			})
+		}
+	}
+
+	recorded, err := s.Repo.RecordAbsences(candidates)
+	if err != nil {
+		return recorded, err
+	}
+	s.notify(recorded)
+	return recorded, nil
+}
+
+// resendPending: mengirim ulang webhook yang sebelumnya gagal. Jendelanya sama
+// dengan Detect (kemarin dan hari ini) supaya baris lama tidak dikirim ulang
+// ketika ABSENCE_WEBHOOK_URL baru diisi.
+func (s *AbsenceService) resendPending(now time.Time) {
+	if s.WebhookURL == "" {
+		return
+	}
+	yesterday := sitetime.StartOfDay(now).AddDate(0, 0, -1)
+	pending, err := s.Repo.GetUnnotified(yesterday)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	s.notify(pending)
+}
+
+// notify: satu request webhook per atasan per tanggal. Kegagalan hanya dicatat di
+// log; baris yang belum terkirim tetap memiliki notified_at kosong.
+func (s *AbsenceService) notify(absences []model.Absence) {
+	if s.WebhookURL == "" || len(absences) == 0 {
+		return
+	}
+
+	groups := make(map[string][]model.Absence)
+	order := []string{}
+	for _, a := range absences {
+		key := a.SupervisorNIK + "|" + a.Date.Format("2006-01-02")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], a)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		payload := model.AbsenceNotification{
+			Event:         "absence.detected",
+			SupervisorNIK: group[0].SupervisorNIK,
+			Date:          group[0].Date.Format("2006-01-02"),
+			Absences:      group,
+		}
+		if err := s.post(payload); err != nil {
+			log.Printf("Webhook ketidakhadiran gagal (atasan %s): %v", payload.SupervisorNIK, err)
+			continue
+		}
+
+		ids := make([]int, len(group))
+		for i, a := range group {
+			ids[i] = a.ID
+		}
+		if err := s.Repo.MarkNotified(ids); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func (s *AbsenceService) post(payload model.AbsenceNotification) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
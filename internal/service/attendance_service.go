@@ -0,0 +1,554 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type AttendanceService struct {
+	LogRepo      *repository.FingerLogRepository
+	UserRepo     *repository.UserRepository
+	ShiftRepo    *repository.ShiftRepository
+	OvertimeRepo *repository.OvertimeRepository
+	HolidayRepo  *repository.HolidayRepository
+	LeaveRepo    *repository.LeaveRepository
+}
+
+func NewAttendanceService(logRepo *repository.FingerLogRepository, userRepo *repository.UserRepository, shiftRepo *repository.ShiftRepository, overtimeRepo *repository.OvertimeRepository, holidayRepo *repository.HolidayRepository, leaveRepo *repository.LeaveRepository) *AttendanceService {
+	return &AttendanceService{
+		LogRepo:      logRepo,
+		UserRepo:     userRepo,
+		ShiftRepo:    shiftRepo,
+		OvertimeRepo: overtimeRepo,
+		HolidayRepo:  holidayRepo,
+		LeaveRepo:    leaveRepo,
+	}
+}
+
+// PlanDay: shift terjadwal satu karyawan pada tanggal tsb, dengan hari libur diperhitungkan
+func (s *AttendanceService) PlanDay(nik string, date time.Time) (model.PlannedShift, error) {
+	date = sitetime.OnDate(date)
+	employee, err := s.UserRepo.GetEmployee(nik, date)
+	if err != nil {
+		return model.PlannedShift{}, err
+	}
+	schedule, err := s.ShiftRepo.LoadScheduleData(date, date)
+	if err != nil {
+		return model.PlannedShift{}, fmt.Errorf("gagal memuat jadwal: %w", err)
+	}
+	holidays, err := s.HolidayRepo.HolidaysBetween(date, date)
+	if err != nil {
+		return model.PlannedShift{}, err
+	}
+
+	planned := ResolveShift(schedule, nik, date)
+	applyHoliday(&planned, HolidayFor(holidays, employee, date))
+	return planned, nil
+}
+
+// WorkingDays: jumlah hari dengan shift terjadwal (bukan off/libur) karyawan
+// pada rentang [from, to]. Tanggal tanpa jadwal tidak dihitung.
+func (s *AttendanceService) WorkingDays(nik string, from, to time.Time) (int, error) {
+	from, to = sitetime.OnDate(from), sitetime.OnDate(to)
+	employee, err := s.UserRepo.GetEmployee(nik, to)
+	if err != nil {
+		return 0, err
+	}
+	schedule, err := s.ShiftRepo.LoadScheduleData(from, to)
+	if err != nil {
+		return 0, fmt.Errorf("gagal memuat jadwal: %w", err)
+	}
+	holidays, err := s.HolidayRepo.HolidaysBetween(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return countWorkingDays(schedule, holidays, employee, from, to), nil
+}
+
+func countWorkingDays(schedule model.ScheduleData, holidays []model.Holiday, employee model.Employee, from, to time.Time) int {
+	days := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !isRestDay(schedule, holidays, employee, day) {
+			days++
+		}
+	}
+	return days
+}
+
+// isRestDay: tidak ada shift terjadwal, yaitu hari off menurut pola, hari libur
+// yang berlaku untuk karyawan, atau tanggal tanpa jadwal sama sekali
+func isRestDay(schedule model.ScheduleData, holidays []model.Holiday, employee model.Employee, day time.Time) bool {
+	planned := ResolveShift(schedule, employee.NIK, day)
+	applyHoliday(&planned, HolidayFor(holidays, employee, day))
+	return planned.Shift == nil
+}
+
+// DailySummary: ringkasan absensi semua karyawan aktif pada tanggal `date`
+func (s *AttendanceService) DailySummary(date time.Time, filter model.OrgFilter) ([]model.DailySummary, error) {
+	day := sitetime.OnDate(date)
+	employees, perEmployee, err := s.summarizePeriod(day, day, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]model.DailySummary, 0, len(employees))
+	for _, e := range employees {
+		summaries = append(summaries, perEmployee[e.NIK]...)
+	}
+	return summaries, nil
+}
+
+// MonthlyRecap: rekap per karyawan untuk bulan `month` (tanggal berapa pun di bulan tsb).
+// Hari setelah hari ini tidak dihitung.
+func (s *AttendanceService) MonthlyRecap(month time.Time, filter model.OrgFilter) ([]model.MonthlyRecap, error) {
+	from, to := monthRange(month)
+
+	recaps := []model.MonthlyRecap{}
+	if to.Before(from) {
+		return recaps, nil
+	}
+
+	employees, perEmployee, err := s.summarizePeriod(from, to, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range employees {
+		recap := model.MonthlyRecap{
+			NIK:            e.NIK,
+			FullName:       e.FullName,
+			DepartmentName: e.DepartmentName,
+			Month:          from.Format("2006-01"),
+		}
+		workedMinutes, overtimeMinutes := 0, 0
+		for _, day := range perEmployee[e.NIK] {
+			if day.ShiftCode != "" {
+				recap.ScheduledDays++
+			}
+			switch day.Status {
+			case model.StatusPresent:
+				recap.DaysPresent++
+			case model.StatusLate:
+				recap.DaysPresent++
+				recap.LateCount++
+			case model.StatusIncomplete:
+				recap.DaysPresent++
+				recap.IncompleteDays++
+			case model.StatusAbsent:
+				recap.Absences++
+			case model.StatusLeave:
+				recap.LeaveDays++
+			}
+			recap.LateMinutes += day.LateMinutes
+			workedMinutes += day.WorkedMinutes
+			overtimeMinutes += day.PaidOvertime
+		}
+		recap.WorkedHours = minutesToHours(workedMinutes)
+		recap.OvertimeHours = minutesToHours(overtimeMinutes)
+		recaps = append(recaps, recap)
+	}
+	return recaps, nil
+}
+
+// Matrix: lembar absensi satu bulan, baris = karyawan, kolom = tanggal.
+// Tanggal setelah hari ini dan di luar masa kerja dibiarkan kosong.
+func (s *AttendanceService) Matrix(month time.Time, filter model.OrgFilter) (model.AttendanceMatrix, error) {
+	from, to := monthRange(month)
+	lastDay := from.AddDate(0, 1, -1)
+
+	matrix := model.AttendanceMatrix{Month: from.Format("2006-01"), Rows: []model.MatrixRow{}}
+	for day := from; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		matrix.Dates = append(matrix.Dates, day.Format("2006-01-02"))
+	}
+	if to.Before(from) {
+		return matrix, nil
+	}
+
+	employees, perEmployee, err := s.summarizePeriod(from, to, filter)
+	if err != nil {
+		return matrix, err
+	}
+
+	for _, e := range employees {
+		row := model.MatrixRow{
+			NIK:            e.NIK,
+			FullName:       e.FullName,
+			DepartmentName: e.DepartmentName,
+			Totals:         make(map[string]int),
+		}
+		byDate := make(map[string]model.DailySummary)
+		for _, day := range perEmployee[e.NIK] {
+			byDate[day.Date] = day
+		}
+
+		for _, date := range matrix.Dates {
+			cell := model.MatrixCell{Date: date}
+			if day, ok := byDate[date]; ok {
+				cell = matrixCell(day)
+				if cell.Code != "" {
+					row.Totals[cell.Code]++
+				}
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+	return matrix, nil
+}
+
+func matrixCell(day model.DailySummary) model.MatrixCell {
+	cell := model.MatrixCell{Date: day.Date, CheckIn: day.CheckIn, CheckOut: day.CheckOut}
+	switch day.Status {
+	case model.StatusPresent, model.StatusLate, model.StatusIncomplete:
+		cell.Code = model.CodeHadir
+		in, out := "?", "?"
+		if day.CheckIn != nil {
+			in = day.CheckIn.In(sitetime.Location()).Format("15:04")
+		}
+		if day.CheckOut != nil {
+			out = day.CheckOut.In(sitetime.Location()).Format("15:04")
+		}
+		cell.Text = in + "-" + out
+		return cell
+	case model.StatusLeave:
+		cell.Code = leaveCode(day.NoteCategory, day.Note)
+		if leaveType := model.FindLeaveType(day.LeaveType); leaveType != nil {
+			cell.Code = leaveType.MatrixCode
+		}
+	case model.StatusAbsent:
+		cell.Code = model.CodeAlpha
+	case model.StatusOff:
+		cell.Code = model.CodeLibur
+	}
+	cell.Text = cell.Code
+	return cell
+}
+
+// leaveCode: kode izin dari kategori catatan (hari tanpa pengajuan cuti). Catatan
+// lama berkategori "other" ditebak dari isinya.
+func leaveCode(category, note string) string {
+	lower := strings.ToLower(note)
+	switch {
+	case category == model.NoteSick:
+		return model.CodeSakit
+	case category == model.NotePermit || category == model.NoteDutyOutside:
+		return model.CodeIzin
+	case strings.Contains(lower, "sakit"):
+		return model.CodeSakit
+	case strings.Contains(lower, "cuti"):
+		return model.CodeCuti
+	default:
+		return model.CodeIzin
+	}
+}
+
+// monthRange: tanggal pertama bulan dan tanggal terakhir yang sudah terjadi
+// (hari terakhir bulan, atau hari ini jika bulan berjalan)
+func monthRange(month time.Time) (time.Time, time.Time) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, sitetime.Location())
+	to := from.AddDate(0, 1, -1)
+	if today := sitetime.Today(); today.Before(to) {
+		to = today
+	}
+	return from, to
+}
+
+// summarizePeriod: memuat karyawan, jadwal, scan dan catatan untuk rentang hari
+// [from, to] sekaligus, lalu menghitung ringkasan harian per karyawan.
+// Hari di luar masa kerja karyawan dilewati.
+func (s *AttendanceService) summarizePeriod(from, to time.Time, filter model.OrgFilter) ([]model.Employee, map[string][]model.DailySummary, error) {
+	from, to = sitetime.OnDate(from), sitetime.OnDate(to)
+	employees, err := s.UserRepo.GetActiveEmployees(from, to, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal mengambil karyawan: %w", err)
+	}
+
+	schedule, scans, err := s.scansByWorkDate(from, to, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	// Key: NIK + tanggal kerja
+	scansByDay := make(map[string][]time.Time)
+	for _, scan := range scans {
+		key := scan.NIK + "|" + scan.Date
+		scansByDay[key] = append(scansByDay[key], scan.Timestamp)
+	}
+
+	notes, err := s.LogRepo.GetNotesBetween(from, to, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal mengambil catatan: %w", err)
+	}
+	notesByDay := make(map[string]model.NoteResponse)
+	for _, n := range notes {
+		notesByDay[n.NIK+"|"+n.Date] = n
+	}
+
+	approvedOvertime, err := s.OvertimeRepo.ApprovedMinutes(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holidays, err := s.HolidayRepo.HolidaysBetween(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	leaves, err := s.LeaveRepo.ApprovedBetween(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	leavesByNIK := make(map[string][]model.LeaveRequest)
+	for _, l := range leaves {
+		leavesByNIK[l.NIK] = append(leavesByNIK[l.NIK], l)
+	}
+
+	perEmployee := make(map[string][]model.DailySummary, len(employees))
+	for _, e := range employees {
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if !employedOn(e, day) {
+				continue
+			}
+			key := e.NIK + "|" + day.Format("2006-01-02")
+			planned := ResolveShift(schedule, e.NIK, day)
+			applyHoliday(&planned, HolidayFor(holidays, e, day))
+			summary := SummarizeDay(planned, day, scansByDay[key])
+			summary.FullName = e.FullName
+			summary.DepartmentName = e.DepartmentName
+			applyLeave(&summary, leaveOn(leavesByNIK[e.NIK], day))
+			applyNote(&summary, notesByDay[key])
+			summary.ApprovedOvertime = approvedOvertime[key]
+			summary.PaidOvertime = min(summary.OvertimeMinutes, summary.ApprovedOvertime)
+			perEmployee[e.NIK] = append(perEmployee[e.NIK], summary)
+		}
+	}
+	return employees, perEmployee, nil
+}
+
+// scansByWorkDate: scan dengan Date berisi tanggal kerja (lihat WorkDate), hanya
+// yang tanggal kerjanya di [from, to]. Jadwal dan scan dimuat satu hari lebih
+// lebar di kedua sisi agar shift malam yang melewati tengah malam ikut terhitung.
+// Scan diambil tanpa filter organisasi: penugasan bisa berubah di tengah periode,
+// penyaringan karyawan dilakukan oleh pemanggil.
+func (s *AttendanceService) scansByWorkDate(from, to time.Time, nik string) (model.ScheduleData, []model.RawFingerLog, error) {
+	before, after := from.AddDate(0, 0, -1), to.AddDate(0, 0, 1)
+	schedule, err := s.ShiftRepo.LoadScheduleData(before, after)
+	if err != nil {
+		return model.ScheduleData{}, nil, fmt.Errorf("gagal memuat jadwal: %w", err)
+	}
+
+	start, end := sitetime.DayRange(before, after)
+	scans, err := s.LogRepo.GetScansBetween(start, end, nik, model.OrgFilter{})
+	if err != nil {
+		return model.ScheduleData{}, nil, fmt.Errorf("gagal mengambil log finger: %w", err)
+	}
+
+	first, last := sitetime.FormatDate(from), sitetime.FormatDate(to)
+	attributed := scans[:0]
+	for _, scan := range scans {
+		scan.Date = sitetime.FormatDate(WorkDate(schedule, scan.NIK, scan.Timestamp))
+		if scan.Date < first || scan.Date > last {
+			continue
+		}
+		attributed = append(attributed, scan)
+	}
+	return schedule, attributed, nil
+}
+
+// FingerLogs: log scan per NIK per tanggal kerja pada rentang [from, to],
+// urut per tanggal lalu NIK. nik kosong = semua karyawan yang lolos filter.
+func (s *AttendanceService) FingerLogs(from, to time.Time, nik string, filter model.OrgFilter) ([]model.FingerLogResult, error) {
+	from, to = sitetime.OnDate(from), sitetime.OnDate(to)
+	employees, err := s.UserRepo.GetActiveEmployees(from, to, filter)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil karyawan: %w", err)
+	}
+	byNIK := make(map[string]model.Employee, len(employees))
+	for _, e := range employees {
+		byNIK[e.NIK] = e
+	}
+
+	_, scans, err := s.scansByWorkDate(from, to, nik)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := scans[:0]
+	for _, scan := range scans {
+		e, ok := byNIK[scan.NIK]
+		if !ok {
+			continue
+		}
+		if day, _ := sitetime.ParseDate(scan.Date); !employedOn(e, day) {
+			continue
+		}
+		rows = append(rows, scan)
+	}
+	// Scan sudah urut per NIK lalu waktu; stable sort menjaga urutan waktu
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Date != rows[j].Date {
+			return rows[i].Date < rows[j].Date
+		}
+		return rows[i].NIK < rows[j].NIK
+	})
+	return groupFingerLogs(rows), nil
+}
+
+// groupFingerLogs: menggabungkan baris scan (sudah urut) menjadi satu entry per NIK per tanggal
+func groupFingerLogs(rows []model.RawFingerLog) []model.FingerLogResult {
+	data := []model.FingerLogResult{}
+	// Key: NIK|tanggal, Value: index di dalam slice 'data'
+	indices := make(map[string]int)
+
+	for _, row := range rows {
+		key := row.NIK + "|" + row.Date
+		entry := model.FingerLogEntry{ID: row.ID, Timestamp: row.Timestamp, Direction: row.Direction}
+		if idx, exists := indices[key]; exists {
+			data[idx].Timestamps = append(data[idx].Timestamps, row.Timestamp)
+			data[idx].Entries = append(data[idx].Entries, entry)
+			continue
+		}
+		data = append(data, model.FingerLogResult{
+			NIK:        row.NIK,
+			FullName:   row.FullName,
+			Date:       row.Date,
+			Timestamps: []time.Time{row.Timestamp},
+			Entries:    []model.FingerLogEntry{entry},
+		})
+		indices[key] = len(data) - 1
+	}
+	return data
+}
+
+func leaveOn(leaves []model.LeaveRequest, day time.Time) *model.LeaveRequest {
+	d := civilDay(day)
+	for i := range leaves {
+		if civilDay(leaves[i].StartDate) <= d && civilDay(leaves[i].EndDate) >= d {
+			return &leaves[i]
+		}
+	}
+	return nil
+}
+
+func employedOn(e model.Employee, day time.Time) bool {
+	d := civilDay(day)
+	if e.StartDate != nil && civilDay(*e.StartDate) > d {
+		return false
+	}
+	if e.EndDate != nil && civilDay(*e.EndDate) < d {
+		return false
+	}
+	return true
+}
+
+// applyNote: hari tanpa scan yang memiliki catatan dianggap izin, bukan mangkir,
+// kecuali catatan alasan terlambat
+func applyNote(summary *model.DailySummary, note model.NoteResponse) {
+	if note.Note == "" && note.Category == "" {
+		return
+	}
+	summary.Note = note.Note
+	summary.NoteCategory = note.Category
+	if summary.Status == model.StatusAbsent && note.Category != model.NoteLateReason {
+		summary.Status = model.StatusLeave
+	}
+}
+
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+// SummarizeDay: menghitung jam masuk/pulang, durasi kerja, istirahat,
+// keterlambatan, pulang cepat dan lembur aktual dari scan satu hari (sudah terurut).
+//
+// Scan pertama = masuk, scan terakhir = pulang. Scan di antaranya dianggap
+// pasangan keluar/masuk istirahat; jika tidak ada, dipakai durasi istirahat shift.
+// Lembur hari kerja dihitung dari jam pulang setelah akhir shift; pada hari
+// libur seluruh jam kerja dihitung lembur.
+func SummarizeDay(planned model.PlannedShift, date time.Time, scans []time.Time) model.DailySummary {
+	summary := model.DailySummary{
+		NIK:       planned.NIK,
+		Date:      date.Format("2006-01-02"),
+		ScanCount: len(scans),
+		IsRestDay: planned.IsOff,
+		Holiday:   planned.Holiday,
+	}
+
+	var shiftStart, shiftEnd time.Time
+	if planned.Shift != nil {
+		shiftStart, shiftEnd = planned.Shift.Window(date)
+		summary.ShiftCode = planned.Shift.Code
+		summary.ShiftStart = &shiftStart
+		summary.ShiftEnd = &shiftEnd
+	}
+
+	switch {
+	case len(scans) == 0:
+		// Mangkir hanya jika ada shift terjadwal; hari off, libur dan tanggal
+		// tanpa jadwal tidak dihitung
+		summary.Status = model.StatusOff
+		if planned.Shift != nil {
+			summary.Status = model.StatusAbsent
+		}
+		return summary
+	case len(scans) == 1:
+		checkIn := scans[0]
+		summary.CheckIn = &checkIn
+		summary.Status = model.StatusIncomplete
+		if planned.Shift != nil {
+			summary.LateMinutes = lateMinutes(checkIn, shiftStart, planned.Shift.GraceMinutes)
+		}
+		return summary
+	}
+
+	checkIn, checkOut := scans[0], scans[len(scans)-1]
+	summary.CheckIn = &checkIn
+	summary.CheckOut = &checkOut
+
+	var breakTime time.Duration
+	for i := 1; i+1 < len(scans)-1; i += 2 {
+		breakTime += scans[i+1].Sub(scans[i])
+	}
+	if breakTime == 0 && planned.Shift != nil {
+		// Istirahat shift hanya dipotong jika rentang kerja cukup panjang
+		shiftBreak := time.Duration(planned.Shift.BreakMinutes) * time.Minute
+		if checkOut.Sub(checkIn) > shiftBreak*2 {
+			breakTime = shiftBreak
+		}
+	}
+	summary.BreakMinutes = int(breakTime / time.Minute)
+	summary.WorkedMinutes = int((checkOut.Sub(checkIn) - breakTime) / time.Minute)
+
+	summary.Status = model.StatusPresent
+	if planned.IsOff {
+		summary.OvertimeMinutes = summary.WorkedMinutes
+	}
+	if planned.Shift != nil {
+		summary.LateMinutes = lateMinutes(checkIn, shiftStart, planned.Shift.GraceMinutes)
+		if checkOut.Before(shiftEnd) {
+			summary.EarlyLeaveMinutes = int(shiftEnd.Sub(checkOut) / time.Minute)
+		}
+		if checkOut.After(shiftEnd) {
+			summary.OvertimeMinutes = int(checkOut.Sub(shiftEnd) / time.Minute)
+		}
+		if summary.LateMinutes > 0 {
+			summary.Status = model.StatusLate
+		}
+	}
+	return summary
+}
+
+// lateMinutes: dihitung dari jam mulai shift, tetapi hanya jika melewati toleransi
+func lateMinutes(checkIn, shiftStart time.Time, graceMinutes int) int {
+	grace := time.Duration(graceMinutes) * time.Minute
+	if !checkIn.After(shiftStart.Add(grace)) {
+		return 0
+	}
+	return int(checkIn.Sub(shiftStart) / time.Minute)
+}
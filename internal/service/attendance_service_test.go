@@ -0,0 +1,116 @@
+package service
+
+import (
+	"Steril-App/model"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCountWorkingDays(t *testing.T) {
+	shiftID := 1
+	workDay := model.PatternDay{ShiftID: &shiftID, ShiftCode: "P"}
+	offDay := model.PatternDay{}
+	// Pola 5 hari kerja + 2 off, hari ke-0 = Senin 1 Des 2025
+	schedule := model.ScheduleData{
+		Shifts: map[int]model.Shift{shiftID: {ID: shiftID, Code: "P"}},
+		Patterns: map[int]model.SchedulePattern{
+			7: {ID: 7, Code: "5-2", Days: []model.PatternDay{workDay, workDay, workDay, workDay, workDay, offDay, offDay}},
+		},
+		Assignments: []model.ScheduleAssignment{
+			{ID: 1, PatternID: 7, NIK: "1001", StartDate: date(2025, 12, 1)},
+		},
+	}
+	otherDept := 2
+	holidays := []model.Holiday{
+		{Date: date(2025, 12, 3), Name: "Libur nasional"},
+		{Date: date(2025, 12, 4), Name: "Libur departemen lain", DepartmentID: &otherDept},
+		{Date: date(2025, 12, 6), Name: "Libur di hari off"},
+	}
+	employee := model.Employee{NIK: "1001", DepartmentID: 1}
+
+	tests := []struct {
+		name     string
+		employee model.Employee
+		from, to time.Time
+		want     int
+	}{
+		{"satu minggu penuh, libur nasional dipotong", employee, date(2025, 12, 1), date(2025, 12, 7), 4},
+		{"hanya hari off", employee, date(2025, 12, 6), date(2025, 12, 7), 0},
+		{"libur departemen lain tetap hari kerja", employee, date(2025, 12, 4), date(2025, 12, 4), 1},
+		{"dua minggu", employee, date(2025, 12, 1), date(2025, 12, 14), 9},
+		{"sebelum jadwal mulai bukan hari kerja", employee, date(2025, 11, 29), date(2025, 11, 30), 0},
+		{"tanpa jadwal", model.Employee{NIK: "2002", DepartmentID: 1}, date(2025, 12, 1), date(2025, 12, 7), 0},
+		{"from setelah to", employee, date(2025, 12, 2), date(2025, 12, 1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countWorkingDays(schedule, holidays, tt.employee, tt.from, tt.to); got != tt.want {
+				t.Errorf("countWorkingDays = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaveOn(t *testing.T) {
+	leaves := []model.LeaveRequest{
+		{ID: 1, StartDate: date(2025, 12, 1), EndDate: date(2025, 12, 3)},
+		{ID: 2, StartDate: date(2025, 12, 10), EndDate: date(2025, 12, 10)},
+	}
+	jakarta := time.FixedZone("WIB", 7*3600)
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want int // 0 = tidak ada cuti
+	}{
+		{"hari pertama", date(2025, 12, 1), 1},
+		{"hari terakhir inklusif", date(2025, 12, 3), 1},
+		{"sehari setelah rentang", date(2025, 12, 4), 0},
+		{"sehari sebelum rentang", date(2025, 11, 30), 0},
+		{"cuti satu hari", date(2025, 12, 10), 2},
+		// Perbandingan per tanggal kalender, jam dan zona waktu diabaikan
+		{"jam malam di zona site", time.Date(2025, 12, 3, 23, 30, 0, 0, jakarta), 1},
+		{"di antara dua cuti", date(2025, 12, 7), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := leaveOn(leaves, tt.day)
+			switch {
+			case tt.want == 0 && got != nil:
+				t.Errorf("leaveOn = cuti %d, want nil", got.ID)
+			case tt.want != 0 && (got == nil || got.ID != tt.want):
+				t.Errorf("leaveOn = %v, want cuti %d", got, tt.want)
+			}
+		})
+	}
+	if leaveOn(nil, date(2025, 12, 1)) != nil {
+		t.Error("leaveOn(nil) harus nil")
+	}
+}
+
+func TestSummarizeDayStatusWithoutScans(t *testing.T) {
+	shift := &model.Shift{ID: 1, Code: "P", StartTime: "08:00", EndTime: "16:00"}
+	day := date(2025, 12, 1)
+
+	tests := []struct {
+		name    string
+		planned model.PlannedShift
+		want    string
+	}{
+		{"shift terjadwal", model.PlannedShift{NIK: "1001", Shift: shift}, model.StatusAbsent},
+		{"hari off pola", model.PlannedShift{NIK: "1001", IsOff: true}, model.StatusOff},
+		{"hari libur", model.PlannedShift{NIK: "1001", IsOff: true, Holiday: "Natal"}, model.StatusOff},
+		{"tanpa jadwal", model.PlannedShift{NIK: "1001"}, model.StatusOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SummarizeDay(tt.planned, day, nil).Status; got != tt.want {
+				t.Errorf("Status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
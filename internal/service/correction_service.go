@@ -0,0 +1,176 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCorrection = errors.New("pengajuan koreksi tidak valid")
+	ErrNotHR             = errors.New("hanya HR yang boleh menyetujui koreksi absensi")
+)
+
+// CorrectionService: pengajuan koreksi absensi oleh line leader. Pengajuan
+// diarahkan ke atasan langsung karyawan (atau HR jika tidak ada), atasan boleh
+// menolak, tetapi hanya HR (ATTENDANCE_HR, daftar actor dipisah koma) yang
+// menyetujui. DecidedBy harus berasal dari token yang diverifikasi handler
+// (ACTOR_TOKENS). Persetujuan langsung diterapkan ke fingerlog.
+type CorrectionService struct {
+	Repo     *repository.CorrectionRepository
+	LogRepo  *repository.FingerLogRepository
+	OrgRepo  *repository.OrganizationRepository
+	UserRepo *repository.UserRepository
+	HR       map[string]bool
+}
+
+func NewCorrectionService(repo *repository.CorrectionRepository, logRepo *repository.FingerLogRepository, orgRepo *repository.OrganizationRepository, userRepo *repository.UserRepository) *CorrectionService {
+	return &CorrectionService{
+		Repo:     repo,
+		LogRepo:  logRepo,
+		OrgRepo:  orgRepo,
+		UserRepo: userRepo,
+		HR:       actorSet(os.Getenv("ATTENDANCE_HR")),
+	}
+}
+
+func (s *CorrectionService) IsHR(actor string) bool {
+	return s.HR[actor]
+}
+
+func (s *CorrectionService) Create(data *model.CreateCorrectionRequest) (model.CorrectionRequest, error) {
+	data.NIK = strings.TrimSpace(data.NIK)
+	data.Reason = strings.TrimSpace(data.Reason)
+	data.RequestedBy = strings.TrimSpace(data.RequestedBy)
+	if data.NIK == "" || data.RequestedBy == "" || data.Reason == "" {
+		return model.CorrectionRequest{}, fmt.Errorf("%w: nik, requested_by dan reason wajib diisi", ErrInvalidCorrection)
+	}
+
+	var timestamp *time.Time
+	var direction string
+	var date time.Time
+	switch data.Action {
+	case model.CorrectionAdd:
+		parsed, err := sitetime.ParseDateTime(data.Timestamp)
+		if err != nil {
+			return model.CorrectionRequest{}, fmt.Errorf("%w: format timestamp harus YYYY-MM-DD HH:mm:ss", ErrInvalidCorrection)
+		}
+		if parsed.After(time.Now()) {
+			return model.CorrectionRequest{}, fmt.Errorf("%w: timestamp tidak boleh di masa depan", ErrInvalidCorrection)
+		}
+		direction = NormalizeDirection(data.Direction)
+		if data.Direction != "" && direction == "" {
+			return model.CorrectionRequest{}, fmt.Errorf("%w: direction harus IN atau OUT", ErrInvalidCorrection)
+		}
+		if _, err := s.UserRepo.GetEmployee(data.NIK, parsed); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return model.CorrectionRequest{}, fmt.Errorf("%w: karyawan %s tidak ditemukan", ErrInvalidCorrection, data.NIK)
+			}
+			return model.CorrectionRequest{}, err
+		}
+		timestamp, date = &parsed, parsed
+
+	case model.CorrectionDelete:
+		scan, err := s.LogRepo.GetActiveScan(data.LogID)
+		if errors.Is(err, repository.ErrFingerLogNotFound) || (err == nil && scan.NIK != data.NIK) {
+			return model.CorrectionRequest{}, fmt.Errorf("%w: log %d milik %s tidak ditemukan", ErrInvalidCorrection, data.LogID, data.NIK)
+		}
+		if err != nil {
+			return model.CorrectionRequest{}, err
+		}
+		pending, err := s.Repo.HasPendingDelete(data.LogID)
+		if err != nil {
+			return model.CorrectionRequest{}, err
+		}
+		if pending {
+			return model.CorrectionRequest{}, fmt.Errorf("%w: sudah ada pengajuan hapus untuk log %d", ErrInvalidCorrection, data.LogID)
+		}
+		date = scan.Timestamp
+
+	default:
+		return model.CorrectionRequest{}, fmt.Errorf("%w: action harus add atau delete", ErrInvalidCorrection)
+	}
+
+	supervisor, err := s.OrgRepo.SupervisorOf(data.NIK, sitetime.StartOfDay(date))
+	if err != nil {
+		return model.CorrectionRequest{}, err
+	}
+	assignedTo := supervisor
+	if assignedTo == "" {
+		assignedTo = model.CorrectionRouteHR
+	}
+	return s.Repo.CreateRequest(data, timestamp, direction, assignedTo)
+}
+
+// Get: pengajuan beserta komentarnya
+func (s *CorrectionService) Get(id int) (model.CorrectionRequest, error) {
+	request, err := s.Repo.GetRequest(id)
+	if err != nil {
+		return model.CorrectionRequest{}, err
+	}
+	request.Comments, err = s.Repo.GetComments(id)
+	return request, err
+}
+
+// Approve: hanya HR. Perubahan fingerlog dicatat di audit atas nama penyetuju
+// dengan alasan dari pengajuan.
+func (s *CorrectionService) Approve(id int, data *model.DecideRequest, clientIP string) (model.CorrectionRequest, error) {
+	data.DecidedBy = strings.TrimSpace(data.DecidedBy)
+	if data.DecidedBy == "" {
+		return model.CorrectionRequest{}, fmt.Errorf("%w: decided_by wajib diisi", ErrInvalidCorrection)
+	}
+	if !s.IsHR(data.DecidedBy) {
+		return model.CorrectionRequest{}, ErrNotHR
+	}
+	request, err := s.Repo.GetRequest(id)
+	if err != nil {
+		return model.CorrectionRequest{}, err
+	}
+
+	audit := model.AuditContext{
+		Actor:    data.DecidedBy,
+		Reason:   fmt.Sprintf("Koreksi #%d (diajukan %s) disetujui: %s", request.ID, request.RequestedBy, request.Reason),
+		ClientIP: clientIP,
+	}
+	approved, err := s.Repo.Approve(id, data, audit)
+	if errors.Is(err, repository.ErrFingerLogNotFound) {
+		return model.CorrectionRequest{}, fmt.Errorf("%w: log %d sudah terhapus, tolak pengajuan ini", ErrInvalidCorrection, *request.LogID)
+	}
+	return approved, err
+}
+
+// Reject: HR atau atasan yang ditunjuk pada pengajuan
+func (s *CorrectionService) Reject(id int, data *model.DecideRequest) (model.CorrectionRequest, error) {
+	data.DecidedBy = strings.TrimSpace(data.DecidedBy)
+	if data.DecidedBy == "" {
+		return model.CorrectionRequest{}, fmt.Errorf("%w: decided_by wajib diisi", ErrInvalidCorrection)
+	}
+	request, err := s.Repo.GetRequest(id)
+	if err != nil {
+		return model.CorrectionRequest{}, err
+	}
+	if request.Status != model.RequestSubmitted {
+		return model.CorrectionRequest{}, repository.ErrRequestNotPending
+	}
+	if !s.IsHR(data.DecidedBy) && data.DecidedBy != request.AssignedTo {
+		return model.CorrectionRequest{}, fmt.Errorf("%w: hanya HR atau atasan yang ditunjuk (%s) yang dapat menolak", ErrInvalidCorrection, request.AssignedTo)
+	}
+	return s.Repo.Reject(id, data)
+}
+
+func (s *CorrectionService) Comment(id int, data *model.AddCommentRequest) (model.CorrectionComment, error) {
+	data.Author = strings.TrimSpace(data.Author)
+	data.Body = strings.TrimSpace(data.Body)
+	if data.Author == "" || data.Body == "" {
+		return model.CorrectionComment{}, fmt.Errorf("%w: author dan body wajib diisi", ErrInvalidCorrection)
+	}
+	if _, err := s.Repo.GetRequest(id); err != nil {
+		return model.CorrectionComment{}, err
+	}
+	return s.Repo.AddComment(id, data)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotAdmin  = errors.New("hanya admin absensi yang boleh melakukan aksi ini")
+	ErrNotEditor = errors.New("hanya HR atau admin absensi yang boleh mengubah log secara langsung, ajukan lewat POST /corrections")
+)
+
+// FingerLogService: perubahan log secara langsung dan kebijakan log yang
+// dihapus lunak. Tambah, hapus dan koreksi langsung hanya untuk HR
+// (ATTENDANCE_HR) dan admin (ATTENDANCE_ADMINS); pihak lain mengajukan koreksi
+// lewat CorrectionService. Pemulihan khusus admin dan selama masa retensi.
+// Actor harus berasal dari token yang diverifikasi handler (ACTOR_TOKENS),
+// bukan dari body request.
+type FingerLogService struct {
+	Repo      *repository.FingerLogRepository
+	Admins    map[string]bool
+	HR        map[string]bool
+	Retention time.Duration // DELETED_LOG_RETENTION_DAYS, default 30 hari
+}
+
+func NewFingerLogService(repo *repository.FingerLogRepository) *FingerLogService {
+	return &FingerLogService{
+		Repo:      repo,
+		Admins:    actorSet(os.Getenv("ATTENDANCE_ADMINS")),
+		HR:        actorSet(os.Getenv("ATTENDANCE_HR")),
+		Retention: time.Duration(envInt("DELETED_LOG_RETENTION_DAYS", 30)) * 24 * time.Hour,
+	}
+}
+
+// actorSet: "A, B,C" -> {A, B, C}
+func actorSet(value string) map[string]bool {
+	set := make(map[string]bool)
+	for _, actor := range strings.Split(value, ",") {
+		if actor = strings.TrimSpace(actor); actor != "" {
+			set[actor] = true
+		}
+	}
+	return set
+}
+
+func (s *FingerLogService) IsAdmin(actor string) bool {
+	return s.Admins[actor]
+}
+
+// CanEdit: HR dan admin boleh mengubah log tanpa pengajuan koreksi
+func (s *FingerLogService) CanEdit(actor string) bool {
+	return s.Admins[actor] || s.HR[actor]
+}
+
+// ListDeleted: log terhapus milik nik (kosong = semua) dengan tanggal scan di
+// [from, to]. from/to zero = yang dihapus selama masa retensi.
+func (s *FingerLogService) ListDeleted(nik string, from, to time.Time) ([]model.DeletedFingerLog, error) {
+	var start, end, deletedSince *time.Time
+	if from.IsZero() || to.IsZero() {
+		since := s.retentionCutoff()
+		deletedSince = &since
+	} else {
+		rangeStart, rangeEnd := sitetime.DayRange(from, to)
+		start, end = &rangeStart, &rangeEnd
+	}
+
+	logs, err := s.Repo.GetDeletedFingerLogs(nik, start, end, deletedSince)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range logs {
+		until := logs[i].DeletedAt.Add(s.Retention).In(sitetime.Location())
+		if logs[i].ReplacedBy == nil && until.After(now) {
+			logs[i].RestorableUntil = &until
+		}
+	}
+	return logs, nil
+}
+
+// AddManual: tambah scan manual; hanya HR atau admin
+func (s *FingerLogService) AddManual(nik string, timestamp time.Time, direction string, audit model.AuditContext) error {
+	if !s.CanEdit(audit.Actor) {
+		return ErrNotEditor
+	}
+	return s.Repo.AddManualFingerLog(nik, timestamp, direction, audit)
+}
+
+// DeleteByTimestamp: hapus lunak scan berdasarkan NIK dan timestamp (klien lama);
+// hanya HR atau admin
+func (s *FingerLogService) DeleteByTimestamp(nik string, timestamp time.Time, audit model.AuditContext) error {
+	if !s.CanEdit(audit.Actor) {
+		return ErrNotEditor
+	}
+	return s.Repo.DeleteFingerLog(nik, timestamp, audit)
+}
+
+// SoftDelete: hapus lunak satu log berdasarkan ID; hanya HR atau admin
+func (s *FingerLogService) SoftDelete(id int64, audit model.AuditContext) (model.AuditScan, error) {
+	if !s.CanEdit(audit.Actor) {
+		return model.AuditScan{}, ErrNotEditor
+	}
+	return s.Repo.SoftDeleteFingerLog(id, audit)
+}
+
+// Correct: koreksi jam/arah satu log berdasarkan ID; hanya HR atau admin
+func (s *FingerLogService) Correct(id int64, timestamp time.Time, direction string, audit model.AuditContext) (model.AuditScan, error) {
+	if !s.CanEdit(audit.Actor) {
+		return model.AuditScan{}, ErrNotEditor
+	}
+	return s.Repo.CorrectFingerLog(id, timestamp, direction, audit)
+}
+
+// Restore: memulihkan log terhapus; hanya admin dan hanya selama masa retensi
+func (s *FingerLogService) Restore(id int64, audit model.AuditContext) (model.AuditScan, error) {
+	if !s.IsAdmin(audit.Actor) {
+		return model.AuditScan{}, ErrNotAdmin
+	}
+	return s.Repo.RestoreFingerLog(id, s.retentionCutoff(), audit)
+}
+
+func (s *FingerLogService) retentionCutoff() time.Time {
+	return time.Now().Add(-s.Retention)
+}
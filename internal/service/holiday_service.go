@@ -0,0 +1,137 @@
+package service
+
+import (
+	"Steril-App/internal/ical"
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHoliday = errors.New("data hari libur tidak valid")
+
+// maxHolidaySpan: batas rentang satu input hari libur, mencegah salah ketik tahun
+const maxHolidaySpan = 31
+
+type HolidayService struct {
+	Repo *repository.HolidayRepository
+}
+
+func NewHolidayService(repo *repository.HolidayRepository) *HolidayService {
+	return &HolidayService{Repo: repo}
+}
+
+// CreateHoliday: end_date opsional; setiap tanggal pada rentang disimpan sebagai satu baris
+func (s *HolidayService) CreateHoliday(data *model.CreateHolidayRequest) ([]model.Holiday, int, error) {
+	if strings.TrimSpace(data.Name) == "" {
+		return nil, 0, fmt.Errorf("%w: nama hari libur wajib diisi", ErrInvalidHoliday)
+	}
+	kind, err := holidayKind(data.Kind)
+	if err != nil {
+		return nil, 0, err
+	}
+	start, err := sitetime.ParseDate(data.Date)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: format date harus YYYY-MM-DD", ErrInvalidHoliday)
+	}
+	end := start
+	if data.EndDate != "" {
+		end, err = sitetime.ParseDate(data.EndDate)
+		if err != nil || end.Before(start) || civilDay(end)-civilDay(start) >= maxHolidaySpan {
+			return nil, 0, fmt.Errorf("%w: end_date tidak valid (maksimal %d hari)", ErrInvalidHoliday, maxHolidaySpan)
+		}
+	}
+
+	holidays := []model.Holiday{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		holidays = append(holidays, model.Holiday{
+			Date:         day,
+			Name:         strings.TrimSpace(data.Name),
+			Kind:         kind,
+			DepartmentID: data.DepartmentID,
+			Site:         data.Site,
+			Source:       "manual",
+		})
+	}
+	return s.Repo.SaveHolidays(holidays)
+}
+
+// ImportICS: setiap tanggal dari setiap VEVENT menjadi hari libur dengan cakupan yang sama
+func (s *HolidayService) ImportICS(r io.Reader, kind string, departmentID *int, site string) (model.HolidayImportResult, error) {
+	result := model.HolidayImportResult{}
+	kind, err := holidayKind(kind)
+	if err != nil {
+		return result, err
+	}
+	events, err := ical.Parse(r)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrInvalidHoliday, err)
+	}
+
+	holidays := []model.Holiday{}
+	for _, event := range events {
+		name := strings.TrimSpace(event.Summary)
+		if name == "" {
+			name = "Hari Libur"
+		}
+		for _, day := range event.Dates() {
+			holidays = append(holidays, model.Holiday{
+				Date:         day,
+				Name:         name,
+				Kind:         kind,
+				DepartmentID: departmentID,
+				Site:         site,
+				Source:       "ics",
+			})
+		}
+	}
+	if len(holidays) == 0 {
+		return result, fmt.Errorf("%w: file tidak berisi event", ErrInvalidHoliday)
+	}
+
+	saved, skipped, err := s.Repo.SaveHolidays(holidays)
+	if err != nil {
+		return result, err
+	}
+	result.Imported = len(saved)
+	result.Skipped = skipped
+	return result, nil
+}
+
+func holidayKind(kind string) (string, error) {
+	switch kind {
+	case "":
+		return model.HolidayPublic, nil
+	case model.HolidayPublic, model.HolidayCompany:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w: kind harus public atau company", ErrInvalidHoliday)
+	}
+}
+
+// HolidayFor: hari libur yang berlaku bagi karyawan pada tanggal tsb, nil jika hari biasa
+func HolidayFor(holidays []model.Holiday, e model.Employee, day time.Time) *model.Holiday {
+	d := civilDay(day)
+	for i := range holidays {
+		h := &holidays[i]
+		if civilDay(h.Date) == d && h.AppliesTo(e.DepartmentID, e.Site) {
+			return h
+		}
+	}
+	return nil
+}
+
+// applyHoliday: hari libur membatalkan shift terjadwal, sehingga ketidakhadiran
+// tidak dihitung mangkir dan kerja pada hari tsb dihitung lembur hari libur
+func applyHoliday(planned *model.PlannedShift, holiday *model.Holiday) {
+	if holiday == nil {
+		return
+	}
+	planned.IsOff = true
+	planned.Shift = nil
+	planned.Holiday = holiday.Name
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSigningDisabled    = errors.New("INTEGRITY_SIGNING_KEY belum diatur, checkpoint tidak bisa ditandatangani")
+	ErrCheckpointTooEarly = errors.New("checkpoint hanya bisa dibuat untuk hari yang sudah berakhir")
+)
+
+// zeroHash: prev_hash baris pertama setiap rantai
+var zeroHash = strings.Repeat("0", 64)
+
+// errStopWalk: menghentikan WalkChain setelah tautan rusak pertama ditemukan
+var errStopWalk = errors.New("stop")
+
+// IntegrityService: verifikasi rantai hash fingerlog/audit_log dan checkpoint
+// harian yang ditandatangani ed25519 (INTEGRITY_SIGNING_KEY).
+type IntegrityService struct {
+	Repo     *repository.IntegrityRepository
+	Interval time.Duration // Seberapa sering job memastikan checkpoint kemarin sudah ada
+	key      ed25519.PrivateKey
+}
+
+func NewIntegrityService(repo *repository.IntegrityRepository) *IntegrityService {
+	key, err := signingKeyFromEnv()
+	if err != nil {
+		log.Println(err)
+	}
+	return &IntegrityService{Repo: repo, Interval: time.Hour, key: key}
+}
+
+// signingKeyFromEnv: base64 seed 32 byte atau private key 64 byte. Kosong = nil.
+func signingKeyFromEnv() (ed25519.PrivateKey, error) {
+	value := os.Getenv("INTEGRITY_SIGNING_KEY")
+	if value == "" {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err == nil {
+		switch len(raw) {
+		case ed25519.SeedSize:
+			return ed25519.NewKeyFromSeed(raw), nil
+		case ed25519.PrivateKeySize:
+			return ed25519.PrivateKey(raw), nil
+		}
+	}
+	return nil, errors.New("INTEGRITY_SIGNING_KEY harus base64 dari seed 32 byte atau private key 64 byte")
+}
+
+// PublicKey: kunci publik (base64) untuk memverifikasi checkpoint, kosong jika tanda tangan mati
+func (s *IntegrityService) PublicKey() string {
+	if s.key == nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// ChainHash: SHA-256 (hex) atas isi kanonik satu baris, sama dengan chain_append di SQL
+func ChainHash(prevHash string, seq int64, fields []string) string {
+	var b strings.Builder
+	b.WriteString(prevHash)
+	b.WriteString("|")
+	b.WriteString(strconv.FormatInt(seq, 10))
+	b.WriteString("|")
+	for _, field := range fields {
+		b.WriteString(strconv.Itoa(len(field)))
+		b.WriteString(":")
+		b.WriteString(field)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// anchor: hash yang tercatat di checkpoint untuk satu seq
+type anchor struct {
+	date string
+	hash string
+}
+
+// Verify: menelusuri setiap rantai dari awal dan melaporkan tautan rusak pertama,
+// lalu mencocokkan hash dengan checkpoint yang tanda tangannya sah.
+func (s *IntegrityService) Verify() (model.VerifyReport, error) {
+	report := model.VerifyReport{Valid: true, CheckedAt: sitetime.Now(), InvalidCheckpoints: []string{}}
+
+	checkpoints, err := s.Repo.GetCheckpoints()
+	if err != nil {
+		return report, err
+	}
+	anchors := make(map[string]map[int64]anchor)
+	for _, checkpoint := range checkpoints {
+		payload, err := openCheckpoint(checkpoint)
+		if err != nil {
+			report.InvalidCheckpoints = append(report.InvalidCheckpoints, checkpoint.Date)
+			report.Valid = false
+			continue
+		}
+		report.Checkpoints++
+		for _, head := range payload.Heads {
+			if head.Seq == 0 {
+				continue
+			}
+			if anchors[head.Chain] == nil {
+				anchors[head.Chain] = make(map[int64]anchor)
+			}
+			anchors[head.Chain][head.Seq] = anchor{date: checkpoint.Date, hash: head.Hash}
+		}
+	}
+
+	for _, chain := range model.Chains {
+		chainReport, err := s.verifyChain(chain, anchors[chain])
+		if err != nil {
+			return report, err
+		}
+		if chainReport.Broken != nil {
+			report.Valid = false
+		}
+		report.Chains = append(report.Chains, chainReport)
+	}
+	return report, nil
+}
+
+func (s *IntegrityService) verifyChain(chain string, anchors map[int64]anchor) (model.ChainReport, error) {
+	report := model.ChainReport{Chain: chain}
+	head, err := s.Repo.Head(chain)
+	if err != nil {
+		return report, err
+	}
+	report.Head = head
+	if report.Unchained, err = s.Repo.UnchainedCount(chain); err != nil {
+		return report, err
+	}
+
+	fail := func(broken model.ChainBreak) {
+		report.Broken = &broken
+	}
+	prevSeq, prevHash := int64(0), zeroHash
+	err = s.Repo.WalkChain(chain, func(record model.ChainRecord) error {
+		report.Verified++
+		switch {
+		case record.Seq != prevSeq+1:
+			fail(model.ChainBreak{Seq: prevSeq + 1, Reason: fmt.Sprintf("baris seq %d sampai %d hilang", prevSeq+1, record.Seq-1)})
+		case record.PrevHash != prevHash:
+			fail(model.ChainBreak{Seq: record.Seq, Reason: "prev_hash tidak sama dengan hash baris sebelumnya", Expected: prevHash, Actual: record.PrevHash})
+		default:
+			if hash := ChainHash(record.PrevHash, record.Seq, record.Fields); hash != record.Hash {
+				fail(model.ChainBreak{Seq: record.Seq, Reason: "isi baris tidak cocok dengan hash-nya", Expected: hash, Actual: record.Hash})
+			} else if a, ok := anchors[record.Seq]; ok && a.hash != record.Hash {
+				fail(model.ChainBreak{Seq: record.Seq, Reason: "hash berbeda dengan checkpoint " + a.date, Expected: a.hash, Actual: record.Hash})
+			}
+		}
+		if report.Broken != nil {
+			return errStopWalk
+		}
+		prevSeq, prevHash = record.Seq, record.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return report, err
+	}
+	if report.Broken != nil {
+		return report, nil
+	}
+
+	// Baris di ujung rantai yang dihapus tidak meninggalkan celah, tetapi head tidak cocok
+	if head.Seq != prevSeq || (head.Seq > 0 && head.Hash != prevHash) {
+		fail(model.ChainBreak{Seq: prevSeq + 1, Reason: fmt.Sprintf("head rantai di seq %d, baris terakhir seq %d", head.Seq, prevSeq), Expected: head.Hash, Actual: prevHash})
+		return report, nil
+	}
+	missing := int64(0)
+	for seq := range anchors {
+		if seq > prevSeq && (missing == 0 || seq < missing) {
+			missing = seq
+		}
+	}
+	if missing > 0 {
+		a := anchors[missing]
+		fail(model.ChainBreak{Seq: missing, Reason: "baris yang tercatat di checkpoint " + a.date + " tidak ada", Expected: a.hash})
+	}
+	return report, nil
+}
+
+// openCheckpoint: memeriksa tanda tangan lalu membaca payload
+func openCheckpoint(checkpoint model.SignedCheckpoint) (model.CheckpointPayload, error) {
+	var payload model.CheckpointPayload
+	publicKey, err := base64.StdEncoding.DecodeString(checkpoint.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return payload, errors.New("kunci publik checkpoint tidak valid")
+	}
+	signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+	if err != nil || !ed25519.Verify(publicKey, checkpoint.Payload, signature) {
+		return payload, errors.New("tanda tangan checkpoint tidak sah")
+	}
+	err = json.Unmarshal(checkpoint.Payload, &payload)
+	return payload, err
+}
+
+// Checkpoint: checkpoint bertanda tangan untuk tanggal `day`. Jika sudah ada,
+// yang tersimpan dikembalikan; jika belum, dibuat dari head rantai pada akhir hari tsb.
+func (s *IntegrityService) Checkpoint(day time.Time) (model.SignedCheckpoint, error) {
+	day = sitetime.OnDate(day)
+	date := day.Format("2006-01-02")
+	existing, err := s.Repo.GetCheckpoint(date)
+	if err == nil || !errors.Is(err, repository.ErrCheckpointNotFound) {
+		return existing, err
+	}
+
+	_, end := sitetime.DayRange(day, day)
+	if end.After(sitetime.Now()) {
+		return model.SignedCheckpoint{}, ErrCheckpointTooEarly
+	}
+	if s.key == nil {
+		return model.SignedCheckpoint{}, ErrSigningDisabled
+	}
+
+	payload := model.CheckpointPayload{Date: date, Timezone: sitetime.Name(), CreatedAt: sitetime.Now()}
+	for _, chain := range model.Chains {
+		head, err := s.Repo.HeadAt(chain, end)
+		if err != nil {
+			return model.SignedCheckpoint{}, err
+		}
+		payload.Heads = append(payload.Heads, head)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return model.SignedCheckpoint{}, err
+	}
+
+	return s.Repo.SaveCheckpoint(model.SignedCheckpoint{
+		Date:      date,
+		Algorithm: "ed25519",
+		Payload:   data,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, data)),
+		PublicKey: s.PublicKey(),
+	})
+}
+
+// Run: memastikan checkpoint kemarin sudah dibuat, saat start lalu setiap Interval
+func (s *IntegrityService) Run(ctx context.Context) {
+	if s.key == nil {
+		log.Println("Checkpoint integritas harian dimatikan (INTEGRITY_SIGNING_KEY kosong)")
+		return
+	}
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		yesterday := sitetime.Today().AddDate(0, 0, -1)
+		if _, err := s.Checkpoint(yesterday); err != nil {
+			log.Printf("Checkpoint integritas %s gagal: %v", yesterday.Format("2006-01-02"), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// Vektor dihitung terpisah dari aturan trigger SQL (migrations/012_hash_chain.sql):
+// sha256(prev_hash || '|' || seq || '|' || chain_field(f1) || chain_field(f2) ...)
+// dengan chain_field(v) = octet_length(v) || ':' || v, NULL diperlakukan sebagai string kosong.
+func TestChainHash(t *testing.T) {
+	zero := strings.Repeat("0", 64)
+	micros := "1765673883123456" // 2025-12-14 07:58:03.123456 +07:00
+
+	tests := []struct {
+		name   string
+		prev   string
+		seq    int64
+		fields []string
+		want   string
+	}{
+		{
+			name:   "fingerlog lengkap",
+			prev:   zero,
+			seq:    1,
+			fields: []string{"1001", micros, "DEV-01", "IN", "device", "false"},
+			want:   "e1de2426d7c33d81103a9c8321254f82a9c09b8e92591ab0645d69458a9f7c90",
+		},
+		{
+			// Kolom NULL di-COALESCE menjadi '' baik di trigger maupun di WalkChain
+			name:   "fingerlog dengan kolom NULL",
+			prev:   zero,
+			seq:    1,
+			fields: []string{"1001", micros, "", "", "", "false"},
+			want:   "ac6846d55748305f43894574fa7fa8a45ed5e559bfa5134362ee2ef2d9357d17",
+		},
+		{
+			name: "audit_log",
+			prev: strings.Repeat("ab", 32),
+			seq:  42,
+			fields: []string{"HR01", "note.save", "1001", "2025-12-14", "", `{"note": "Sakit demam"}`,
+				"Surat dokter", "10.0.0.5", micros},
+			want: "9978e0805c48b7c1660245c6cad90abd2e28e96137f1ce4c83b261ac9466d57c",
+		},
+		{
+			// octet_length menghitung byte, bukan karakter
+			name:   "panjang dalam byte UTF-8",
+			prev:   zero,
+			seq:    7,
+			fields: []string{"Ünal", "x"},
+			want:   "a1fa22b8fadb37ea3ee88e5e4a2017d5f46a02bc1f3e4a28a6ca0967b582f186",
+		},
+		{
+			name:   "awalan panjang memisahkan field",
+			prev:   zero,
+			seq:    7,
+			fields: []string{"1:2", "3"},
+			want:   "beaa2b1f00fb61e9f92184aaad058556ebee4e443e1b6f0596c377c9d2c1c156",
+		},
+		{
+			name:   "awalan panjang memisahkan field (geser)",
+			prev:   zero,
+			seq:    7,
+			fields: []string{"1", "2:3"},
+			want:   "ddc42804c47e2669806a8872deafe13a39b90468fe8a9f9d3fdcf182ab99e95d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChainHash(tt.prev, tt.seq, tt.fields); got != tt.want {
+				t.Errorf("ChainHash() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
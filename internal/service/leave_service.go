@@ -0,0 +1,161 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrInvalidLeave = errors.New("pengajuan cuti tidak valid")
+
+// maxLeaveSpan: batas rentang satu pengajuan (cuti melahirkan 3 bulan)
+const maxLeaveSpan = 92
+
+// LeaveService: seperti lembur, pengajuan diputuskan atasan langsung atau HR
+// (ATTENDANCE_HR) jika karyawan tidak memiliki atasan. Pemutus dan pengubah
+// hak cuti harus berasal dari token yang diverifikasi handler (ACTOR_TOKENS).
+type LeaveService struct {
+	Repo       *repository.LeaveRepository
+	OrgRepo    *repository.OrganizationRepository
+	Attendance *AttendanceService
+	HR         map[string]bool
+}
+
+func NewLeaveService(repo *repository.LeaveRepository, orgRepo *repository.OrganizationRepository, attendance *AttendanceService) *LeaveService {
+	return &LeaveService{
+		Repo:       repo,
+		OrgRepo:    orgRepo,
+		Attendance: attendance,
+		HR:         actorSet(os.Getenv("ATTENDANCE_HR")),
+	}
+}
+
+// CreateRequest: jumlah hari dihitung dari hari kerja terjadwal pada rentang,
+// hari off dan hari libur tidak ikut dipotong
+func (s *LeaveService) CreateRequest(data *model.CreateLeaveRequest) (model.LeaveRequest, error) {
+	if data.NIK == "" || data.RequestedBy == "" || strings.TrimSpace(data.Reason) == "" {
+		return model.LeaveRequest{}, fmt.Errorf("%w: nik, requested_by dan reason wajib diisi", ErrInvalidLeave)
+	}
+	leaveType := model.FindLeaveType(data.LeaveType)
+	if leaveType == nil {
+		return model.LeaveRequest{}, fmt.Errorf("%w: jenis cuti '%s' tidak dikenal", ErrInvalidLeave, data.LeaveType)
+	}
+	start, err := sitetime.ParseDate(data.StartDate)
+	if err != nil {
+		return model.LeaveRequest{}, fmt.Errorf("%w: format start_date harus YYYY-MM-DD", ErrInvalidLeave)
+	}
+	end := start
+	if data.EndDate != "" {
+		end, err = sitetime.ParseDate(data.EndDate)
+		if err != nil || end.Before(start) || civilDay(end)-civilDay(start) >= maxLeaveSpan {
+			return model.LeaveRequest{}, fmt.Errorf("%w: end_date tidak valid (maksimal %d hari)", ErrInvalidLeave, maxLeaveSpan)
+		}
+	}
+
+	overlap, err := s.Repo.HasOverlap(data.NIK, start, end)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if overlap {
+		return model.LeaveRequest{}, fmt.Errorf("%w: sudah ada pengajuan cuti pada rentang tanggal tsb", ErrInvalidLeave)
+	}
+
+	days, err := s.Attendance.WorkingDays(data.NIK, start, end)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return model.LeaveRequest{}, fmt.Errorf("%w: karyawan %s tidak ditemukan", ErrInvalidLeave, data.NIK)
+	}
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if days == 0 {
+		return model.LeaveRequest{}, fmt.Errorf("%w: rentang tanggal tidak berisi hari kerja", ErrInvalidLeave)
+	}
+
+	if leaveType.DeductsBalance {
+		balance, err := s.Repo.GetBalance(data.NIK, start.Year())
+		if err != nil {
+			return model.LeaveRequest{}, err
+		}
+		if available := balance.Remaining - balance.Pending; days > available {
+			return model.LeaveRequest{}, fmt.Errorf("%w: sisa cuti %d hari (termasuk yang menunggu persetujuan), diajukan %d hari", ErrInvalidLeave, available, days)
+		}
+	}
+	return s.Repo.CreateRequest(data, start, end, days)
+}
+
+// Approve: saldo diperiksa ulang karena bisa berubah sejak pengajuan dibuat;
+// pemeriksaan dan keputusan berjalan dalam satu transaksi di repository
+func (s *LeaveService) Approve(id int, data *model.DecideRequest) (model.LeaveRequest, error) {
+	if _, err := s.checkDecision(id, data); err != nil {
+		return model.LeaveRequest{}, err
+	}
+	request, balance, err := s.Repo.Approve(id, data)
+	if errors.Is(err, repository.ErrLeaveBalanceExceeded) {
+		return model.LeaveRequest{}, fmt.Errorf("%w: sisa cuti %d hari, diajukan %d hari", ErrInvalidLeave, balance.Remaining, request.Days)
+	}
+	return request, err
+}
+
+func (s *LeaveService) Reject(id int, data *model.DecideRequest) (model.LeaveRequest, error) {
+	if _, err := s.checkDecision(id, data); err != nil {
+		return model.LeaveRequest{}, err
+	}
+	return s.Repo.Decide(id, model.RequestRejected, data)
+}
+
+func (s *LeaveService) checkDecision(id int, data *model.DecideRequest) (model.LeaveRequest, error) {
+	if data.DecidedBy == "" {
+		return model.LeaveRequest{}, fmt.Errorf("%w: decided_by wajib diisi", ErrInvalidLeave)
+	}
+	request, err := s.Repo.GetRequest(id)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if request.Status != model.RequestSubmitted {
+		return model.LeaveRequest{}, repository.ErrRequestNotPending
+	}
+	supervisor, err := s.OrgRepo.SupervisorOf(request.NIK, request.StartDate)
+	if err != nil {
+		return model.LeaveRequest{}, err
+	}
+	if err := checkDecider(data.DecidedBy, supervisor, s.HR); err != nil {
+		return model.LeaveRequest{}, err
+	}
+	return request, nil
+}
+
+// SetBalance: hak cuti hanya boleh diubah HR atau atasan langsung karyawan saat ini
+func (s *LeaveService) SetBalance(nik, actor string, data *model.SetLeaveBalanceRequest) (model.LeaveBalance, error) {
+	if data.Year < 2000 || data.EntitledDays < 0 {
+		return model.LeaveBalance{}, fmt.Errorf("%w: year dan entitled_days tidak valid", ErrInvalidLeave)
+	}
+	if !s.HR[actor] {
+		supervisor, err := s.OrgRepo.SupervisorOf(nik, sitetime.StartOfDay(sitetime.Now()))
+		if err != nil {
+			return model.LeaveBalance{}, err
+		}
+		if supervisor == "" || supervisor != actor {
+			return model.LeaveBalance{}, fmt.Errorf("%w: hak cuti hanya dapat diubah HR atau atasan langsung", ErrNotApprover)
+		}
+	}
+	if err := s.Repo.SetBalance(nik, data); err != nil {
+		return model.LeaveBalance{}, err
+	}
+	return s.Repo.GetBalance(nik, data.Year)
+}
+
+// applyLeave: hari tanpa scan yang tercakup cuti disetujui ditandai cuti
+func applyLeave(summary *model.DailySummary, leave *model.LeaveRequest) {
+	if leave == nil || summary.Status != model.StatusAbsent {
+		return
+	}
+	summary.Status = model.StatusLeave
+	summary.LeaveType = leave.LeaveType
+	if leaveType := model.FindLeaveType(leave.LeaveType); leaveType != nil {
+		summary.Note = leaveType.Name
+	}
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"Steril-App/internal/blobstore"
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidNote       = errors.New("catatan tidak valid")
+	ErrAttachmentCorrupt = errors.New("isi file lampiran tidak sesuai metadata")
+)
+
+// Batas satu kali penerapan massal
+const (
+	maxBulkNoteDays    = 31
+	maxBulkNoteTargets = 5000
+)
+
+// Jenis file lampiran yang diterima, dideteksi dari isi file (bukan nama/header)
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+}
+
+// NoteService: catatan harian yang melibatkan banyak karyawan/tanggal, serta
+// lampiran catatan (surat dokter, surat izin) yang disimpan di blob store
+type NoteService struct {
+	Repo               *repository.FingerLogRepository
+	UserRepo           *repository.UserRepository
+	Attendance         *AttendanceService
+	Attachments        *repository.NoteAttachmentRepository
+	Store              blobstore.Store
+	MaxAttachmentBytes int64 // NOTE_ATTACHMENT_MAX_MB, default 5 MB
+}
+
+func NewNoteService(repo *repository.FingerLogRepository, userRepo *repository.UserRepository, attendance *AttendanceService, attachments *repository.NoteAttachmentRepository, store blobstore.Store) *NoteService {
+	return &NoteService{
+		Repo:               repo,
+		UserRepo:           userRepo,
+		Attendance:         attendance,
+		Attachments:        attachments,
+		Store:              store,
+		MaxAttachmentBytes: int64(envInt("NOTE_ATTACHMENT_MAX_MB", 5)) << 20,
+	}
+}
+
+// ApplyBulk: menerapkan satu catatan ke karyawan di data.NIKs dan/atau filter
+// organisasi, pada setiap tanggal [from, to] selama masih dalam masa kerjanya.
+// Hari tanpa shift terjadwal (off, libur, tanpa jadwal) dilewati kecuali
+// include_rest_days diisi, supaya hari istirahat tidak tampak sebagai izin.
+// Contoh: pelatihan satu departemen, atau dinas luar satu minggu.
+func (s *NoteService) ApplyBulk(data *model.BulkNoteRequest, audit model.AuditContext) (model.BulkNoteResult, error) {
+	if data.Category == "" {
+		data.Category = model.NoteOther
+	}
+	if !model.IsNoteCategory(data.Category) {
+		return model.BulkNoteResult{}, fmt.Errorf("%w: kategori harus sick, permit, duty_outside, late_reason atau other", ErrInvalidNote)
+	}
+	if strings.TrimSpace(data.Note) == "" {
+		return model.BulkNoteResult{}, fmt.Errorf("%w: note wajib diisi", ErrInvalidNote)
+	}
+	if data.To == "" {
+		data.To = data.From
+	}
+	from, errFrom := sitetime.ParseDate(data.From)
+	to, errTo := sitetime.ParseDate(data.To)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return model.BulkNoteResult{}, fmt.Errorf("%w: from dan to wajib diisi dengan format YYYY-MM-DD", ErrInvalidNote)
+	}
+	if days := civilDay(to) - civilDay(from) + 1; days > maxBulkNoteDays {
+		return model.BulkNoteResult{}, fmt.Errorf("%w: rentang tanggal maksimal %d hari", ErrInvalidNote, maxBulkNoteDays)
+	}
+	// Tanpa NIK maupun filter berarti seluruh karyawan; ditolak untuk mencegah salah kirim
+	if len(data.NIKs) == 0 && data.OrgFilter.IsEmpty() {
+		return model.BulkNoteResult{}, fmt.Errorf("%w: isi niks atau filter department_id/line_id/supervisor_nik", ErrInvalidNote)
+	}
+
+	employees, err := s.UserRepo.GetActiveEmployees(from, to, data.OrgFilter)
+	if err != nil {
+		return model.BulkNoteResult{}, fmt.Errorf("gagal mengambil karyawan: %w", err)
+	}
+	if len(data.NIKs) > 0 {
+		wanted := make(map[string]bool, len(data.NIKs))
+		for _, nik := range data.NIKs {
+			wanted[strings.TrimSpace(nik)] = true
+		}
+		selected := employees[:0]
+		for _, e := range employees {
+			if wanted[e.NIK] {
+				selected = append(selected, e)
+				delete(wanted, e.NIK)
+			}
+		}
+		if len(wanted) > 0 {
+			missing := make([]string, 0, len(wanted))
+			for nik := range wanted {
+				missing = append(missing, nik)
+			}
+			sort.Strings(missing)
+			return model.BulkNoteResult{}, fmt.Errorf("%w: karyawan tidak ditemukan atau tidak aktif: %s", ErrInvalidNote, strings.Join(missing, ", "))
+		}
+		employees = selected
+	}
+
+	schedule, err := s.Attendance.ShiftRepo.LoadScheduleData(from, to)
+	if err != nil {
+		return model.BulkNoteResult{}, fmt.Errorf("gagal memuat jadwal: %w", err)
+	}
+	holidays, err := s.Attendance.HolidayRepo.HolidaysBetween(from, to)
+	if err != nil {
+		return model.BulkNoteResult{}, err
+	}
+
+	var targets []model.NoteTarget
+	restDays := 0
+	for _, e := range employees {
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if !employedOn(e, day) {
+				continue
+			}
+			if !data.IncludeRestDays && isRestDay(schedule, holidays, e, day) {
+				restDays++
+				continue
+			}
+			targets = append(targets, model.NoteTarget{NIK: e.NIK, Date: sitetime.FormatDate(day)})
+		}
+	}
+	if len(targets) > maxBulkNoteTargets {
+		return model.BulkNoteResult{}, fmt.Errorf("%w: maksimal %d catatan sekaligus, %d diminta", ErrInvalidNote, maxBulkNoteTargets, len(targets))
+	}
+
+	applied, err := s.Repo.SaveNotes(targets, data.Category, data.Note, data.Overwrite, audit)
+	if err != nil {
+		return model.BulkNoteResult{}, err
+	}
+	return model.BulkNoteResult{
+		Employees: len(employees),
+		Applied:   applied,
+		Skipped:   len(targets) - applied,
+		RestDays:  restDays,
+	}, nil
+}
+
+// DeleteNote: hapus catatan beserta lampirannya. File lampiran dihapus dari
+// blob store setelah transaksi berhasil; kegagalan hanya dicatat di log.
+func (s *NoteService) DeleteNote(nik, date string, audit model.AuditContext) error {
+	attachments, err := s.Repo.DeleteUserNote(nik, date, audit)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		s.deleteBlob(a.StorageKey)
+	}
+	return nil
+}
+
+// AddAttachment: unggah lampiran ke catatan yang sudah ada. Jenis file dicek
+// dari isinya, ukuran dibatasi MaxAttachmentBytes, checksum SHA-256 dihitung
+// saat file ditulis.
+func (s *NoteService) AddAttachment(nik, date, filename string, r io.Reader, audit model.AuditContext) (model.NoteAttachment, error) {
+	if nik == "" {
+		return model.NoteAttachment{}, fmt.Errorf("%w: nik wajib diisi", ErrInvalidNote)
+	}
+	day, err := sitetime.ParseDate(date)
+	if err != nil {
+		return model.NoteAttachment{}, fmt.Errorf("%w: format date harus YYYY-MM-DD", ErrInvalidNote)
+	}
+	filename = filepath.Base(filepath.Clean("/" + strings.ReplaceAll(filename, "\\", "/")))
+	if filename == "/" || filename == "." || !utf8.ValidString(filename) || len(filename) > 255 {
+		return model.NoteAttachment{}, fmt.Errorf("%w: nama file tidak valid", ErrInvalidNote)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return model.NoteAttachment{}, fmt.Errorf("%w: file kosong", ErrInvalidNote)
+		}
+		return model.NoteAttachment{}, fmt.Errorf("gagal membaca file: %w", err)
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if !allowedAttachmentTypes[contentType] {
+		return model.NoteAttachment{}, fmt.Errorf("%w: jenis file %s tidak diizinkan, hanya PDF, JPEG, PNG atau WebP", ErrInvalidNote, contentType)
+	}
+
+	key, err := attachmentKey(sitetime.FormatDate(day))
+	if err != nil {
+		return model.NoteAttachment{}, err
+	}
+	hash := sha256.New()
+	counter := &countingWriter{}
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), s.MaxAttachmentBytes+1)
+	if err := s.Store.Put(key, io.TeeReader(body, io.MultiWriter(hash, counter))); err != nil {
+		return model.NoteAttachment{}, err
+	}
+	if counter.n > s.MaxAttachmentBytes {
+		s.deleteBlob(key)
+		return model.NoteAttachment{}, fmt.Errorf("%w: ukuran file maksimal %d MB", ErrInvalidNote, s.MaxAttachmentBytes>>20)
+	}
+
+	attachment, err := s.Attachments.CreateAttachment(model.NoteAttachment{
+		NIK:         nik,
+		Date:        sitetime.FormatDate(day),
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   counter.n,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+	}, audit)
+	if err != nil {
+		s.deleteBlob(key)
+		return model.NoteAttachment{}, err
+	}
+	return attachment, nil
+}
+
+// ReadAttachment: metadata dan isi lampiran. Isi dibaca penuh (paling besar
+// SizeBytes) lalu ukuran dan SHA-256-nya dicocokkan dengan metadata; file yang
+// berubah di penyimpanan menghasilkan ErrAttachmentCorrupt.
+func (s *NoteService) ReadAttachment(id int) (model.NoteAttachment, []byte, error) {
+	attachment, err := s.Attachments.GetAttachment(id)
+	if err != nil {
+		return model.NoteAttachment{}, nil, err
+	}
+	file, err := s.Store.Open(attachment.StorageKey)
+	if err != nil {
+		return model.NoteAttachment{}, nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, attachment.SizeBytes+1))
+	if err != nil {
+		return model.NoteAttachment{}, nil, fmt.Errorf("gagal membaca lampiran %d: %w", id, err)
+	}
+	sum := sha256.Sum256(content)
+	if int64(len(content)) != attachment.SizeBytes || hex.EncodeToString(sum[:]) != attachment.SHA256 {
+		return model.NoteAttachment{}, nil, fmt.Errorf("%w (lampiran %d)", ErrAttachmentCorrupt, id)
+	}
+	return attachment, content, nil
+}
+
+func (s *NoteService) DeleteAttachment(id int, audit model.AuditContext) error {
+	attachment, err := s.Attachments.DeleteAttachment(id, audit)
+	if err != nil {
+		return err
+	}
+	s.deleteBlob(attachment.StorageKey)
+	return nil
+}
+
+func (s *NoteService) deleteBlob(key string) {
+	if err := s.Store.Delete(key); err != nil {
+		log.Printf("gagal menghapus lampiran %s: %v", key, err)
+	}
+}
+
+// attachmentKey: "<YYYY-MM-DD>/<32 hex acak>", nama asli file tidak dipakai di disk
+func attachmentKey(date string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("gagal membuat key lampiran: %w", err)
+	}
+	return date + "/" + hex.EncodeToString(random), nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
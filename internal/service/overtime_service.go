@@ -0,0 +1,257 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidOvertime = errors.New("pengajuan lembur tidak valid")
+	ErrNotApprover     = errors.New("bukan pemutus pengajuan ini")
+)
+
+// Batas menit satu pengajuan. Lembur hari kerja dibatasi 4 jam
+// (PP 35/2021), lembur hari libur sampai jam ke-12 (minggu kerja 5 hari).
+const (
+	maxWorkdayOvertimeMinutes = 4 * 60
+	maxRestDayOvertimeMinutes = 12 * 60
+)
+
+// OvertimeService: pengajuan lembur diputuskan atasan langsung karyawan, atau
+// HR (ATTENDANCE_HR) jika karyawan tidak memiliki atasan. DecidedBy harus
+// berasal dari token yang diverifikasi handler (ACTOR_TOKENS).
+type OvertimeService struct {
+	Repo         *repository.OvertimeRepository
+	OrgRepo      *repository.OrganizationRepository
+	Attendance   *AttendanceService
+	WorkweekDays int // 5 atau 6 hari kerja per minggu, menentukan tingkat lembur hari libur
+	HR           map[string]bool
+}
+
+func NewOvertimeService(repo *repository.OvertimeRepository, orgRepo *repository.OrganizationRepository, attendance *AttendanceService) *OvertimeService {
+	return &OvertimeService{
+		Repo:         repo,
+		OrgRepo:      orgRepo,
+		Attendance:   attendance,
+		WorkweekDays: WorkweekDaysFromEnv(),
+		HR:           actorSet(os.Getenv("ATTENDANCE_HR")),
+	}
+}
+
+// WorkweekDaysFromEnv: WORKWEEK_DAYS, default 5
+func WorkweekDaysFromEnv() int {
+	days, err := strconv.Atoi(os.Getenv("WORKWEEK_DAYS"))
+	if err != nil || (days != 5 && days != 6) {
+		return 5
+	}
+	return days
+}
+
+// CreateRequest: lembur diajukan sebelum dijalankan, jadi tanggal tidak boleh lampau
+func (s *OvertimeService) CreateRequest(data *model.CreateOvertimeRequest) (model.OvertimeRequest, error) {
+	if data.NIK == "" || data.RequestedBy == "" || data.Reason == "" {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: nik, requested_by dan reason wajib diisi", ErrInvalidOvertime)
+	}
+	date, err := sitetime.ParseDate(data.Date)
+	if err != nil {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: format date harus YYYY-MM-DD", ErrInvalidOvertime)
+	}
+	if civilDay(date) < civilDay(sitetime.Now()) {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: lembur harus diajukan sebelum tanggal pelaksanaan", ErrInvalidOvertime)
+	}
+	if data.PlannedMinutes <= 0 || data.PlannedMinutes > maxRestDayOvertimeMinutes {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: planned_minutes harus 1-%d", ErrInvalidOvertime, maxRestDayOvertimeMinutes)
+	}
+
+	planned, err := s.Attendance.PlanDay(data.NIK, date)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: karyawan %s tidak ditemukan", ErrInvalidOvertime, data.NIK)
+	}
+	if err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	if !planned.IsOff && data.PlannedMinutes > maxWorkdayOvertimeMinutes {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: lembur hari kerja maksimal %d menit", ErrInvalidOvertime, maxWorkdayOvertimeMinutes)
+	}
+
+	exists, err := s.Repo.HasActiveRequest(data.NIK, date)
+	if err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	if exists {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: sudah ada pengajuan lembur untuk %s pada %s", ErrInvalidOvertime, data.NIK, data.Date)
+	}
+	return s.Repo.CreateRequest(data, date)
+}
+
+// Approve: persetujuan hanya sah sebelum hari lembur berakhir dan hanya oleh
+// pemutus pengajuan (lihat checkDecider)
+func (s *OvertimeService) Approve(id int, data *model.DecideRequest) (model.OvertimeRequest, error) {
+	request, err := s.checkDecision(id, data)
+	if err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	if civilDay(request.Date) < civilDay(sitetime.Now()) {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: pengajuan untuk tanggal yang sudah lewat tidak dapat disetujui", ErrInvalidOvertime)
+	}
+	return s.Repo.Decide(id, model.RequestApproved, data)
+}
+
+func (s *OvertimeService) Reject(id int, data *model.DecideRequest) (model.OvertimeRequest, error) {
+	if _, err := s.checkDecision(id, data); err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	return s.Repo.Decide(id, model.RequestRejected, data)
+}
+
+func (s *OvertimeService) checkDecision(id int, data *model.DecideRequest) (model.OvertimeRequest, error) {
+	if data.DecidedBy == "" {
+		return model.OvertimeRequest{}, fmt.Errorf("%w: decided_by wajib diisi", ErrInvalidOvertime)
+	}
+	request, err := s.Repo.GetRequest(id)
+	if err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	if request.Status != model.RequestSubmitted {
+		return model.OvertimeRequest{}, repository.ErrRequestNotPending
+	}
+	supervisor, err := s.OrgRepo.SupervisorOf(request.NIK, request.Date)
+	if err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	if err := checkDecider(data.DecidedBy, supervisor, s.HR); err != nil {
+		return model.OvertimeRequest{}, err
+	}
+	return request, nil
+}
+
+// checkDecider: atasan langsung memutuskan; karyawan tanpa atasan diputuskan HR
+func checkDecider(actor, supervisor string, hr map[string]bool) error {
+	if supervisor == "" {
+		if !hr[actor] {
+			return fmt.Errorf("%w: karyawan tidak memiliki atasan langsung, hanya HR yang dapat memutuskan", ErrNotApprover)
+		}
+		return nil
+	}
+	if actor != supervisor {
+		return fmt.Errorf("%w: hanya atasan langsung (%s) yang dapat memutuskan", ErrNotApprover, supervisor)
+	}
+	return nil
+}
+
+// Daily: lembur per karyawan pada satu tanggal. Karyawan tanpa lembur aktual
+// maupun persetujuan tidak ditampilkan.
+func (s *OvertimeService) Daily(date time.Time, filter model.OrgFilter) ([]model.OvertimeDay, error) {
+	summaries, err := s.Attendance.DailySummary(date, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []model.OvertimeDay{}
+	for _, summary := range summaries {
+		if summary.OvertimeMinutes == 0 && summary.ApprovedOvertime == 0 {
+			continue
+		}
+		days = append(days, OvertimeForDay(summary, s.WorkweekDays))
+	}
+	return days, nil
+}
+
+// Monthly: total lembur per karyawan dalam satu bulan. Tingkat pengali dihitung
+// per hari lalu dijumlahkan.
+func (s *OvertimeService) Monthly(month time.Time, filter model.OrgFilter) ([]model.OvertimeMonthly, error) {
+	from, to := monthRange(month)
+
+	totals := []model.OvertimeMonthly{}
+	if to.Before(from) {
+		return totals, nil
+	}
+
+	employees, perEmployee, err := s.Attendance.summarizePeriod(from, to, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range employees {
+		total := model.OvertimeMonthly{
+			NIK:            e.NIK,
+			FullName:       e.FullName,
+			DepartmentName: e.DepartmentName,
+			Month:          from.Format("2006-01"),
+		}
+		for _, summary := range perEmployee[e.NIK] {
+			if summary.OvertimeMinutes == 0 && summary.ApprovedOvertime == 0 {
+				continue
+			}
+			day := OvertimeForDay(summary, s.WorkweekDays)
+			if day.PaidMinutes > 0 {
+				total.Days++
+			}
+			total.ActualMinutes += day.ActualMinutes
+			total.ApprovedMinutes += day.ApprovedMinutes
+			total.PaidMinutes += day.PaidMinutes
+			total.Tiers.X1_5 += day.Tiers.X1_5
+			total.Tiers.X2 += day.Tiers.X2
+			total.Tiers.X3 += day.Tiers.X3
+			total.Tiers.X4 += day.Tiers.X4
+		}
+		total.WeightedHours = weightedHours(total.Tiers)
+		totals = append(totals, total)
+	}
+	return totals, nil
+}
+
+// OvertimeForDay: memecah lembur dibayar sebuah DailySummary ke tingkat pengali
+func OvertimeForDay(summary model.DailySummary, workweekDays int) model.OvertimeDay {
+	day := model.OvertimeDay{
+		NIK:             summary.NIK,
+		FullName:        summary.FullName,
+		DepartmentName:  summary.DepartmentName,
+		Date:            summary.Date,
+		IsRestDay:       summary.IsRestDay,
+		ActualMinutes:   summary.OvertimeMinutes,
+		ApprovedMinutes: summary.ApprovedOvertime,
+		PaidMinutes:     summary.PaidOvertime,
+	}
+	day.Tiers = OvertimeTiers(day.PaidMinutes, day.IsRestDay, workweekDays)
+	day.WeightedHours = weightedHours(day.Tiers)
+	return day
+}
+
+// OvertimeTiers: pembagian menit lembur ke pengali upah.
+//   - Hari kerja: jam pertama 1,5x, jam berikutnya 2x.
+//   - Hari libur, 5 hari kerja: 8 jam pertama 2x, jam ke-9 3x, jam ke-10 dst 4x.
+//   - Hari libur, 6 hari kerja: 7 jam pertama 2x, jam ke-8 3x, jam ke-9 dst 4x.
+func OvertimeTiers(minutes int, restDay bool, workweekDays int) model.OvertimeTiers {
+	tiers := model.OvertimeTiers{}
+	if minutes <= 0 {
+		return tiers
+	}
+	if !restDay {
+		tiers.X1_5 = min(minutes, 60)
+		tiers.X2 = minutes - tiers.X1_5
+		return tiers
+	}
+
+	base := 8 * 60
+	if workweekDays == 6 {
+		base = 7 * 60
+	}
+	tiers.X2 = min(minutes, base)
+	tiers.X3 = min(minutes-tiers.X2, 60)
+	tiers.X4 = minutes - tiers.X2 - tiers.X3
+	return tiers
+}
+
+// weightedHours: jumlah jam x pengali, dikali 1/173 upah sebulan untuk upah lembur
+func weightedHours(t model.OvertimeTiers) float64 {
+	weighted := 1.5*float64(t.X1_5) + 2*float64(t.X2) + 3*float64(t.X3) + 4*float64(t.X4)
+	return math.Round(weighted/60*100) / 100
+}
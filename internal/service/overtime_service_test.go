@@ -0,0 +1,97 @@
+package service
+
+import (
+	"Steril-App/model"
+	"errors"
+	"testing"
+)
+
+func TestOvertimeTiers(t *testing.T) {
+	tests := []struct {
+		name         string
+		minutes      int
+		restDay      bool
+		workweekDays int
+		want         model.OvertimeTiers
+	}{
+		{"tanpa lembur", 0, false, 5, model.OvertimeTiers{}},
+		{"menit negatif", -30, true, 5, model.OvertimeTiers{}},
+
+		// Hari kerja: jam pertama 1,5x, berikutnya 2x (sama untuk 5 dan 6 hari kerja)
+		{"hari kerja 59 menit", 59, false, 5, model.OvertimeTiers{X1_5: 59}},
+		{"hari kerja tepat 1 jam", 60, false, 5, model.OvertimeTiers{X1_5: 60}},
+		{"hari kerja 61 menit", 61, false, 5, model.OvertimeTiers{X1_5: 60, X2: 1}},
+		{"hari kerja 3 jam", 180, false, 5, model.OvertimeTiers{X1_5: 60, X2: 120}},
+		{"hari kerja 6 hari/minggu", 180, false, 6, model.OvertimeTiers{X1_5: 60, X2: 120}},
+
+		// Hari libur, 5 hari kerja: 8 jam 2x, jam ke-9 3x, jam ke-10 dst 4x
+		{"libur 5 hari, 8 jam", 480, true, 5, model.OvertimeTiers{X2: 480}},
+		{"libur 5 hari, 8 jam 1 menit", 481, true, 5, model.OvertimeTiers{X2: 480, X3: 1}},
+		{"libur 5 hari, 9 jam", 540, true, 5, model.OvertimeTiers{X2: 480, X3: 60}},
+		{"libur 5 hari, 9 jam 1 menit", 541, true, 5, model.OvertimeTiers{X2: 480, X3: 60, X4: 1}},
+		{"libur 5 hari, 11 jam", 660, true, 5, model.OvertimeTiers{X2: 480, X3: 60, X4: 120}},
+
+		// Hari libur, 6 hari kerja: 7 jam 2x, jam ke-8 3x, jam ke-9 dst 4x
+		{"libur 6 hari, 7 jam", 420, true, 6, model.OvertimeTiers{X2: 420}},
+		{"libur 6 hari, 7 jam 1 menit", 421, true, 6, model.OvertimeTiers{X2: 420, X3: 1}},
+		{"libur 6 hari, 8 jam", 480, true, 6, model.OvertimeTiers{X2: 420, X3: 60}},
+		{"libur 6 hari, 8 jam 1 menit", 481, true, 6, model.OvertimeTiers{X2: 420, X3: 60, X4: 1}},
+		{"libur 6 hari, 10 jam", 600, true, 6, model.OvertimeTiers{X2: 420, X3: 60, X4: 120}},
+
+		{"libur, 1 jam", 60, true, 5, model.OvertimeTiers{X2: 60}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OvertimeTiers(tt.minutes, tt.restDay, tt.workweekDays)
+			if got != tt.want {
+				t.Errorf("OvertimeTiers(%d, %v, %d) = %+v, want %+v", tt.minutes, tt.restDay, tt.workweekDays, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightedHours(t *testing.T) {
+	tests := []struct {
+		tiers model.OvertimeTiers
+		want  float64
+	}{
+		{model.OvertimeTiers{}, 0},
+		{model.OvertimeTiers{X1_5: 60, X2: 120}, 5.5},
+		{model.OvertimeTiers{X2: 480, X3: 60, X4: 60}, 23},
+		{model.OvertimeTiers{X1_5: 20}, 0.5},
+		{model.OvertimeTiers{X1_5: 1}, 0.03}, // 0,025 dibulatkan ke atas
+	}
+	for _, tt := range tests {
+		if got := weightedHours(tt.tiers); got != tt.want {
+			t.Errorf("weightedHours(%+v) = %v, want %v", tt.tiers, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDecider(t *testing.T) {
+	hr := map[string]bool{"HR01": true}
+	tests := []struct {
+		name       string
+		actor      string
+		supervisor string
+		allowed    bool
+	}{
+		{"atasan langsung", "SPV001", "SPV001", true},
+		{"atasan lain", "SPV002", "SPV001", false},
+		{"HR saat ada atasan", "HR01", "SPV001", false},
+		{"tanpa atasan, HR", "HR01", "", true},
+		{"tanpa atasan, bukan HR", "SPV002", "", false},
+		{"tanpa atasan, actor kosong", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDecider(tt.actor, tt.supervisor, hr)
+			if tt.allowed && err != nil {
+				t.Errorf("checkDecider = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrNotApprover) {
+				t.Errorf("checkDecider = %v, want ErrNotApprover", err)
+			}
+		})
+	}
+}
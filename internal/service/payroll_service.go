@@ -0,0 +1,207 @@
+package service
+
+import (
+	"Steril-App/internal/payroll"
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPayroll    = errors.New("permintaan payroll tidak valid")
+	ErrUnmappedEmployees = errors.New("karyawan belum memiliki ID payroll")
+)
+
+// maxPayrollDays: periode cut-off terpanjang (misal 21 s/d 20 bulan berikutnya
+// masih jauh di bawah batas ini)
+const maxPayrollDays = 62
+
+// PayrollService: total absensi per periode dan export ke file import payroll
+// menurut template bernama
+type PayrollService struct {
+	Repo         *repository.PayrollRepository
+	Attendance   *AttendanceService
+	WorkweekDays int
+}
+
+func NewPayrollService(repo *repository.PayrollRepository, attendance *AttendanceService) *PayrollService {
+	return &PayrollService{Repo: repo, Attendance: attendance, WorkweekDays: WorkweekDaysFromEnv()}
+}
+
+// Period: month=YYYY-MM, atau from/to. Tanggal setelah hari ini tidak dihitung.
+func (s *PayrollService) Period(request model.PayrollPeriodRequest) (time.Time, time.Time, error) {
+	if request.Month != "" {
+		month, err := sitetime.ParseMonth(request.Month)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: format month harus YYYY-MM", ErrInvalidPayroll)
+		}
+		from, to := monthRange(month)
+		return from, to, nil
+	}
+
+	from, errFrom := sitetime.ParseDate(request.From)
+	to, errTo := sitetime.ParseDate(request.To)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: isi month, atau from dan to dengan format YYYY-MM-DD", ErrInvalidPayroll)
+	}
+	if civilDay(to)-civilDay(from)+1 > maxPayrollDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: periode maksimal %d hari", ErrInvalidPayroll, maxPayrollDays)
+	}
+	if today := sitetime.Today(); today.Before(to) {
+		to = today
+	}
+	return from, to, nil
+}
+
+// Totals: total absensi per karyawan untuk [from, to]
+func (s *PayrollService) Totals(from, to time.Time, filter model.OrgFilter) ([]model.PayrollTotals, error) {
+	totals := []model.PayrollTotals{}
+	if to.Before(from) {
+		return totals, nil
+	}
+
+	employees, perEmployee, err := s.Attendance.summarizePeriod(from, to, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range employees {
+		totals = append(totals, sumPayrollTotals(e, perEmployee[e.NIK], from, to, s.WorkweekDays))
+	}
+	return totals, nil
+}
+
+// sumPayrollTotals: menjumlahkan ringkasan harian satu karyawan pada periode [from, to]
+func sumPayrollTotals(e model.Employee, days []model.DailySummary, from, to time.Time, workweekDays int) model.PayrollTotals {
+	total := model.PayrollTotals{
+		NIK:            e.NIK,
+		FullName:       e.FullName,
+		DepartmentName: e.DepartmentName,
+		From:           sitetime.FormatDate(from),
+		To:             sitetime.FormatDate(to),
+		LeaveByType:    map[string]int{},
+	}
+	workedMinutes, paidMinutes := 0, 0
+	for _, day := range days {
+		if day.ShiftCode != "" {
+			total.Workdays++
+		}
+		switch day.Status {
+		case model.StatusPresent:
+			total.DaysPresent++
+		case model.StatusLate:
+			total.DaysPresent++
+			total.LateCount++
+		case model.StatusIncomplete:
+			total.DaysPresent++
+			total.IncompleteDays++
+		case model.StatusAbsent:
+			total.Absences++
+		case model.StatusLeave:
+			total.LeaveDays++
+			total.LeaveByType[leaveKey(day)]++
+		}
+		total.LateMinutes += day.LateMinutes
+		total.EarlyLeaveMinutes += day.EarlyLeaveMinutes
+		workedMinutes += day.WorkedMinutes
+		paidMinutes += day.PaidOvertime
+
+		tiers := OvertimeForDay(day, workweekDays).Tiers
+		total.OvertimeTiers.X1_5 += tiers.X1_5
+		total.OvertimeTiers.X2 += tiers.X2
+		total.OvertimeTiers.X3 += tiers.X3
+		total.OvertimeTiers.X4 += tiers.X4
+	}
+	total.WorkedHours = minutesToHours(workedMinutes)
+	total.OvertimeHours = minutesToHours(paidMinutes)
+	total.OvertimeWeightedHours = weightedHours(total.OvertimeTiers)
+	return total
+}
+
+// leaveKey: kode jenis cuti yang disetujui; izin dari catatan harian
+// dipetakan dari kategorinya (sick/permit sama dengan kode cuti)
+func leaveKey(day model.DailySummary) string {
+	if day.LeaveType != "" {
+		return day.LeaveType
+	}
+	switch day.NoteCategory {
+	case model.NoteSick:
+		return model.LeaveSick
+	case model.NotePermit:
+		return model.LeavePermit
+	case "":
+		return model.NoteOther
+	}
+	return day.NoteCategory
+}
+
+func (s *PayrollService) SaveTemplate(name string, t *model.PayrollTemplate) (model.PayrollTemplate, error) {
+	t.Name = name
+	if err := payroll.Normalize(t); err != nil {
+		return model.PayrollTemplate{}, fmt.Errorf("%w: %v", ErrInvalidPayroll, err)
+	}
+	return s.Repo.SaveTemplate(*t)
+}
+
+func (s *PayrollService) SaveEmployeeIDs(template string, ids []model.PayrollEmployeeID) error {
+	if _, err := s.Repo.GetTemplate(template); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if strings.TrimSpace(id.NIK) == "" {
+			return fmt.Errorf("%w: nik wajib diisi di setiap pemetaan", ErrInvalidPayroll)
+		}
+	}
+	return s.Repo.SaveEmployeeIDs(template, ids)
+}
+
+// Export: template dan baris file untuk periode [from, to]. Untuk template
+// identifier "mapped", karyawan tanpa ID payroll ditangani sesuai template.Unmapped.
+func (s *PayrollService) Export(name string, from, to time.Time, filter model.OrgFilter) (model.PayrollTemplate, []payroll.Row, error) {
+	template, err := s.Repo.GetTemplate(name)
+	if err != nil {
+		return model.PayrollTemplate{}, nil, err
+	}
+	if err := payroll.Normalize(&template); err != nil {
+		return model.PayrollTemplate{}, nil, fmt.Errorf("%w: template %s: %v", ErrInvalidPayroll, name, err)
+	}
+
+	totals, err := s.Totals(from, to, filter)
+	if err != nil {
+		return model.PayrollTemplate{}, nil, err
+	}
+
+	var ids map[string]string
+	if template.Identifier == model.IdentifierMapped {
+		if ids, err = s.Repo.EmployeeIDs(name); err != nil {
+			return model.PayrollTemplate{}, nil, err
+		}
+	}
+
+	rows := make([]payroll.Row, 0, len(totals))
+	var unmapped []string
+	for _, total := range totals {
+		id := total.NIK
+		if ids != nil {
+			externalID, ok := ids[total.NIK]
+			switch {
+			case ok:
+				id = externalID
+			case template.Unmapped == model.UnmappedSkip:
+				continue
+			case template.Unmapped == model.UnmappedError:
+				unmapped = append(unmapped, total.NIK)
+				continue
+			}
+		}
+		rows = append(rows, payroll.Row{EmployeeID: id, Totals: total})
+	}
+	if len(unmapped) > 0 {
+		return model.PayrollTemplate{}, nil, fmt.Errorf("%w: %s", ErrUnmappedEmployees, strings.Join(unmapped, ", "))
+	}
+	return template, rows, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"Steril-App/model"
+	"testing"
+)
+
+func TestLeaveKey(t *testing.T) {
+	tests := []struct {
+		name string
+		day  model.DailySummary
+		want string
+	}{
+		{"cuti disetujui", model.DailySummary{LeaveType: model.LeaveAnnual}, model.LeaveAnnual},
+		{"jenis cuti menang atas kategori catatan", model.DailySummary{LeaveType: model.LeaveMaternity, NoteCategory: model.NoteSick}, model.LeaveMaternity},
+		{"catatan sakit", model.DailySummary{NoteCategory: model.NoteSick}, model.LeaveSick},
+		{"catatan izin", model.DailySummary{NoteCategory: model.NotePermit}, model.LeavePermit},
+		{"catatan dinas luar", model.DailySummary{NoteCategory: model.NoteDutyOutside}, model.NoteDutyOutside},
+		{"catatan lain", model.DailySummary{NoteCategory: model.NoteOther}, model.NoteOther},
+		{"catatan tanpa kategori", model.DailySummary{Note: "izin keluarga"}, model.NoteOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaveKey(tt.day); got != tt.want {
+				t.Errorf("leaveKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumPayrollTotals(t *testing.T) {
+	employee := model.Employee{NIK: "1001", FullName: "Budi", DepartmentName: "Produksi"}
+	days := []model.DailySummary{
+		{ShiftCode: "P", Status: model.StatusPresent, WorkedMinutes: 480, PaidOvertime: 90},
+		{ShiftCode: "P", Status: model.StatusLate, WorkedMinutes: 465, LateMinutes: 15},
+		{ShiftCode: "P", Status: model.StatusIncomplete, WorkedMinutes: 0},
+		{ShiftCode: "P", Status: model.StatusAbsent},
+		{ShiftCode: "P", Status: model.StatusLeave, LeaveType: model.LeaveAnnual},
+		{ShiftCode: "P", Status: model.StatusLeave, NoteCategory: model.NoteSick},
+		{ShiftCode: "P", Status: model.StatusPresent, WorkedMinutes: 450, EarlyLeaveMinutes: 30},
+		// Kerja di hari libur: tanpa shift, lembur hari libur 2x
+		{Status: model.StatusPresent, IsRestDay: true, WorkedMinutes: 240, PaidOvertime: 240},
+		{Status: model.StatusOff, IsRestDay: true},
+	}
+
+	got := sumPayrollTotals(employee, days, date(2025, 12, 1), date(2025, 12, 9), 5)
+
+	if got.NIK != "1001" || got.FullName != "Budi" || got.DepartmentName != "Produksi" || got.From != "2025-12-01" || got.To != "2025-12-09" {
+		t.Errorf("identitas/periode salah: %+v", got)
+	}
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"Workdays", got.Workdays, 7},
+		{"DaysPresent", got.DaysPresent, 5},
+		{"LateCount", got.LateCount, 1},
+		{"IncompleteDays", got.IncompleteDays, 1},
+		{"Absences", got.Absences, 1},
+		{"LeaveDays", got.LeaveDays, 2},
+		{"LeaveByType[annual]", got.LeaveByType[model.LeaveAnnual], 1},
+		{"LeaveByType[sick]", got.LeaveByType[model.LeaveSick], 1},
+		{"LateMinutes", got.LateMinutes, 15},
+		{"EarlyLeaveMinutes", got.EarlyLeaveMinutes, 30},
+		{"OvertimeTiers.X1_5", got.OvertimeTiers.X1_5, 60},
+		{"OvertimeTiers.X2", got.OvertimeTiers.X2, 30 + 240},
+		{"OvertimeTiers.X3", got.OvertimeTiers.X3, 0},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	// 1635 menit kerja, 330 menit lembur dibayar, 1,5*60 + 2*270 = 630 menit tertimbang
+	if got.WorkedHours != 27.25 || got.OvertimeHours != 5.5 || got.OvertimeWeightedHours != 10.5 {
+		t.Errorf("jam = %v/%v/%v, want 27.25/5.5/10.5", got.WorkedHours, got.OvertimeHours, got.OvertimeWeightedHours)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// sequenceWindow: scan sebelumnya yang lebih lama dari ini tidak dipakai
+// untuk menebak arah (dianggap sudah periode kerja yang berbeda)
+const sequenceWindow = 16 * time.Hour
+
+// ScanService: mencatat scan dari perangkat, menentukan arah dan debounce
+type ScanService struct {
+	LogRepo         *repository.FingerLogRepository
+	ShiftRepo       *repository.ShiftRepository
+	DeviceRepo      *repository.DeviceRepository
+	DebounceSeconds int // Jendela debounce global jika perangkat tidak punya pengaturan sendiri
+}
+
+func NewScanService(logRepo *repository.FingerLogRepository, shiftRepo *repository.ShiftRepository, deviceRepo *repository.DeviceRepository, debounceSeconds int) *ScanService {
+	return &ScanService{
+		LogRepo:         logRepo,
+		ShiftRepo:       shiftRepo,
+		DeviceRepo:      deviceRepo,
+		DebounceSeconds: debounceSeconds,
+	}
+}
+
+// NormalizeDirection: "in"/"masuk" -> IN, "out"/"keluar"/"pulang" -> OUT, selain itu kosong
+func NormalizeDirection(value string) string {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "IN", "MASUK":
+		return model.DirectionIn
+	case "OUT", "KELUAR", "PULANG":
+		return model.DirectionOut
+	default:
+		return ""
+	}
+}
+
+// RecordScan: urutan penentuan arah: dari sensor, default perangkat, lalu tebakan server.
+// Mengembalikan true jika scan ditandai duplikat.
+func (s *ScanService) RecordScan(nik, deviceCode, direction string) (bool, error) {
+	now := sitetime.Now()
+	source := model.DirectionSourceSensor
+	direction = NormalizeDirection(direction)
+
+	if direction == "" && deviceCode != "" {
+		device, err := s.DeviceRepo.GetDevice(deviceCode)
+		if err != nil {
+			log.Printf("Gagal membaca perangkat %s: %v", deviceCode, err)
+		} else if device != nil && device.Direction != "" {
+			direction = device.Direction
+			source = model.DirectionSourceDevice
+		}
+	}
+
+	if direction == "" {
+		// Scan duplikat tidak ikut laporan, jadi arahnya tidak perlu ditebak
+		duplicate, err := s.LogRepo.IsDuplicateScan(nik, deviceCode, s.DebounceSeconds)
+		if err != nil {
+			return false, err
+		}
+		if duplicate {
+			source = ""
+		} else {
+			inferred, err := s.inferDirection(nik, now)
+			if err != nil {
+				return false, err
+			}
+			direction = inferred
+			source = model.DirectionSourceInferred
+		}
+	}
+
+	return s.LogRepo.AddScan(nik, deviceCode, direction, source, s.DebounceSeconds)
+}
+
+func (s *ScanService) inferDirection(nik string, now time.Time) (string, error) {
+	last, err := s.LogRepo.LastScan(nik)
+	if err != nil {
+		return "", err
+	}
+
+	// Jadwal kemarin ikut dimuat: scan pulang shift malam setelah tengah
+	// malam milik shift kemarin, sama seperti atribusi di laporan
+	day := sitetime.StartOfDay(now)
+	schedule, err := s.ShiftRepo.LoadScheduleData(day.AddDate(0, 0, -1), day.AddDate(0, 0, 1))
+	if err != nil {
+		return "", fmt.Errorf("gagal memuat jadwal: %w", err)
+	}
+	workDate := WorkDate(schedule, nik, now)
+	return InferDirection(now, last, ResolveShift(schedule, nik, workDate), workDate), nil
+}
+
+// InferDirection: aturan urutan lebih diutamakan (masuk dan keluar bergantian
+// dalam satu periode kerja). Tanpa scan sebelumnya, scan setelah pertengahan
+// shift pada tanggal kerja workDate dianggap keluar (lupa scan masuk), selain
+// itu masuk.
+func InferDirection(now time.Time, last *model.FingerLogEntry, planned model.PlannedShift, workDate time.Time) string {
+	if last != nil && last.Direction != "" && now.Sub(last.Timestamp) < sequenceWindow {
+		if last.Direction == model.DirectionIn {
+			return model.DirectionOut
+		}
+		return model.DirectionIn
+	}
+
+	if planned.Shift != nil {
+		start, end := planned.Shift.Window(workDate)
+		if now.After(start.Add(end.Sub(start) / 2)) {
+			return model.DirectionOut
+		}
+	}
+	return model.DirectionIn
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"Steril-App/model"
+	"testing"
+	"time"
+)
+
+func TestInferDirection(t *testing.T) {
+	morning := model.PlannedShift{Shift: &model.Shift{Code: "P", StartTime: "07:00", EndTime: "15:00"}}
+	night := model.PlannedShift{Shift: &model.Shift{Code: "M", StartTime: "23:00", EndTime: "07:00"}}
+	at := func(d, h, m int) time.Time {
+		return time.Date(2025, 12, d, h, m, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		last     *model.FingerLogEntry
+		planned  model.PlannedShift
+		workDate time.Time
+		want     string
+	}{
+		{"tanpa jadwal", at(15, 16, 0), nil, model.PlannedShift{}, date(2025, 12, 15), model.DirectionIn},
+		{"pagi sebelum pertengahan", at(15, 6, 55), nil, morning, date(2025, 12, 15), model.DirectionIn},
+		{"pagi setelah pertengahan", at(15, 15, 5), nil, morning, date(2025, 12, 15), model.DirectionOut},
+		{"malam, masuk sebelum tengah malam", at(15, 22, 50), nil, night, date(2025, 12, 15), model.DirectionIn},
+		// Pulang 07:05 tanggal 16 milik shift malam tanggal 15
+		{"malam, pulang setelah tengah malam", at(16, 7, 5), nil, night, date(2025, 12, 15), model.DirectionOut},
+		{"urutan: setelah IN", at(15, 12, 0), &model.FingerLogEntry{Timestamp: at(15, 7, 0), Direction: model.DirectionIn}, morning, date(2025, 12, 15), model.DirectionOut},
+		{"urutan: setelah OUT", at(15, 14, 0), &model.FingerLogEntry{Timestamp: at(15, 12, 0), Direction: model.DirectionOut}, morning, date(2025, 12, 15), model.DirectionIn},
+		{"scan sebelumnya di luar jendela", at(16, 6, 55), &model.FingerLogEntry{Timestamp: at(15, 7, 0), Direction: model.DirectionIn}, morning, date(2025, 12, 16), model.DirectionIn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferDirection(tt.now, tt.last, tt.planned, tt.workDate); got != tt.want {
+				t.Errorf("InferDirection = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
+	"Steril-App/model"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("data jadwal tidak valid")
+
+type ShiftService struct {
+	Repo *repository.ShiftRepository
+}
+
+func NewShiftService(repo *repository.ShiftRepository) *ShiftService {
+	return &ShiftService{Repo: repo}
+}
+
+func (s *ShiftService) CreateShift(data *model.CreateShiftRequest) (model.Shift, error) {
+	if data.Code == "" || data.Name == "" {
+		return model.Shift{}, fmt.Errorf("%w: kode dan nama shift wajib diisi", ErrInvalidSchedule)
+	}
+	start, err := model.ParseClock(data.StartTime)
+	if err != nil {
+		return model.Shift{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	end, err := model.ParseClock(data.EndTime)
+	if err != nil {
+		return model.Shift{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	if start == end {
+		return model.Shift{}, fmt.Errorf("%w: jam mulai dan selesai tidak boleh sama", ErrInvalidSchedule)
+	}
+	if data.GraceMinutes < 0 || data.BreakMinutes < 0 {
+		return model.Shift{}, fmt.Errorf("%w: toleransi dan istirahat tidak boleh negatif", ErrInvalidSchedule)
+	}
+	if data.WindowBeforeMinutes == nil {
+		before := model.DefaultWindowBeforeMinutes
+		data.WindowBeforeMinutes = &before
+	}
+	if data.WindowAfterMinutes == nil {
+		after := model.DefaultWindowAfterMinutes
+		data.WindowAfterMinutes = &after
+	}
+	// Jendela dibatasi 12 jam agar jendela kemarin dan besok tidak saling menumpuk penuh
+	if *data.WindowBeforeMinutes < 0 || *data.WindowAfterMinutes < 0 ||
+		*data.WindowBeforeMinutes > 720 || *data.WindowAfterMinutes > 720 {
+		return model.Shift{}, fmt.Errorf("%w: jendela scan harus 0-720 menit", ErrInvalidSchedule)
+	}
+	return s.Repo.CreateShift(data)
+}
+
+func (s *ShiftService) CreatePattern(data *model.CreatePatternRequest) (model.SchedulePattern, error) {
+	if data.Code == "" || data.Name == "" || len(data.Days) == 0 {
+		return model.SchedulePattern{}, fmt.Errorf("%w: kode, nama dan urutan hari wajib diisi", ErrInvalidSchedule)
+	}
+	pattern, err := s.Repo.CreatePattern(data)
+	if errors.Is(err, repository.ErrUnknownShiftCode) {
+		return model.SchedulePattern{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	return pattern, err
+}
+
+func (s *ShiftService) AssignSchedule(data *model.AssignScheduleRequest) (model.ScheduleAssignment, error) {
+	if data.PatternID == 0 {
+		return model.ScheduleAssignment{}, fmt.Errorf("%w: pattern_id wajib diisi", ErrInvalidSchedule)
+	}
+	if (data.NIK == "") == (data.LineID == nil) {
+		return model.ScheduleAssignment{}, fmt.Errorf("%w: isi salah satu dari nik atau line_id", ErrInvalidSchedule)
+	}
+	startDate, err := sitetime.ParseDate(data.StartDate)
+	if err != nil {
+		return model.ScheduleAssignment{}, fmt.Errorf("%w: format start_date harus YYYY-MM-DD", ErrInvalidSchedule)
+	}
+	var endDate *time.Time
+	if data.EndDate != "" {
+		t, err := sitetime.ParseDate(data.EndDate)
+		if err != nil || t.Before(startDate) {
+			return model.ScheduleAssignment{}, fmt.Errorf("%w: end_date tidak valid", ErrInvalidSchedule)
+		}
+		endDate = &t
+	}
+	return s.Repo.AssignSchedule(data, startDate, endDate)
+}
+
+// PlannedShift: shift yang direncanakan untuk satu user pada tanggal tertentu
+func (s *ShiftService) PlannedShift(nik string, date time.Time) (model.PlannedShift, error) {
+	data, err := s.Repo.LoadScheduleData(date, date)
+	if err != nil {
+		return model.PlannedShift{}, fmt.Errorf("gagal memuat data jadwal: %w", err)
+	}
+	return ResolveShift(data, nik, date), nil
+}
+
+// ResolveShift: jadwal khusus user lebih diutamakan daripada jadwal line.
+// Jika tidak ada jadwal sama sekali, Shift bernilai nil dan IsOff false.
+func ResolveShift(data model.ScheduleData, nik string, date time.Time) model.PlannedShift {
+	planned := model.PlannedShift{NIK: nik, Date: date.Format("2006-01-02")}
+
+	assignment, source := findScheduleAssignment(data, nik, date)
+	if assignment == nil {
+		return planned
+	}
+
+	pattern, ok := data.Patterns[assignment.PatternID]
+	if !ok || len(pattern.Days) == 0 {
+		return planned
+	}
+	planned.PatternCode = pattern.Code
+	planned.Source = source
+
+	offset := civilDay(date) - civilDay(assignment.StartDate)
+	idx := offset % len(pattern.Days)
+	if idx < 0 {
+		idx += len(pattern.Days)
+	}
+
+	day := pattern.Days[idx]
+	if day.ShiftID == nil {
+		planned.IsOff = true
+		return planned
+	}
+	if shift, ok := data.Shifts[*day.ShiftID]; ok {
+		planned.Shift = &shift
+	}
+	return planned
+}
+
+// WorkDate: tanggal kerja (tengah malam zona site) untuk scan pada instan ts.
+// Shift terjadwal kemarin, hari ini dan besok diperiksa; scan masuk ke shift yang
+// ScanWindow-nya memuat ts. Jika lebih dari satu cocok, dipilih shift yang jam
+// kerjanya paling dekat dengan ts. Tanpa shift yang cocok dipakai tanggal kalender.
+func WorkDate(data model.ScheduleData, nik string, ts time.Time) time.Time {
+	calendar := sitetime.StartOfDay(ts)
+	workDate, best := calendar, time.Duration(-1)
+	for offset := -1; offset <= 1; offset++ {
+		day := calendar.AddDate(0, 0, offset)
+		planned := ResolveShift(data, nik, day)
+		if planned.Shift == nil {
+			continue
+		}
+		windowStart, windowEnd := planned.Shift.ScanWindow(day)
+		if ts.Before(windowStart) || !ts.Before(windowEnd) {
+			continue
+		}
+
+		start, end := planned.Shift.Window(day)
+		var distance time.Duration
+		if ts.Before(start) {
+			distance = start.Sub(ts)
+		} else if ts.After(end) {
+			distance = ts.Sub(end)
+		}
+		if best < 0 || distance < best {
+			workDate, best = day, distance
+		}
+	}
+	return workDate
+}
+
+func findScheduleAssignment(data model.ScheduleData, nik string, date time.Time) (*model.ScheduleAssignment, string) {
+	day := civilDay(date)
+	active := func(start time.Time, end *time.Time) bool {
+		return civilDay(start) <= day && (end == nil || civilDay(*end) >= day)
+	}
+
+	// Assignments sudah diurutkan dari start_date terbaru
+	for i := range data.Assignments {
+		a := &data.Assignments[i]
+		if a.NIK == nik && active(a.StartDate, a.EndDate) {
+			return a, "user"
+		}
+	}
+
+	var lineID *int
+	for _, ua := range data.UserAssignments {
+		if ua.NIK == nik && ua.LineID != nil && active(ua.EffectiveFrom, ua.EffectiveTo) {
+			lineID = ua.LineID
+			break
+		}
+	}
+	if lineID == nil {
+		return nil, ""
+	}
+	for i := range data.Assignments {
+		a := &data.Assignments[i]
+		if a.LineID != nil && *a.LineID == *lineID && active(a.StartDate, a.EndDate) {
+			return a, "line"
+		}
+	}
+	return nil, ""
+}
+
+// civilDay: nomor hari kalender (tanpa memperhatikan zona waktu) untuk perbandingan tanggal
+func civilDay(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"Steril-App/internal/repository"
+	"Steril-App/internal/sitetime"
 	"Steril-App/model"
 	"errors"
 	"fmt"
+	"time"
 )
 
 type UserService struct {
@@ -20,9 +22,50 @@ func NewUserService(userRepo *repository.UserRepository, fingerRepo *repository.
 }
 
 var ErrUserAlreadyExists = errors.New("user sudah terdaftar")
+var ErrInvalidEmployment = errors.New("data kepegawaian tidak valid")
+
+// validateEmployment: cek jenis hubungan kerja dan urutan tanggal kepegawaian
+func validateEmployment(data *model.EmploymentData) error {
+	switch data.EmploymentType {
+	case "", model.EmploymentPermanent, model.EmploymentContract, model.EmploymentOutsourcing:
+	default:
+		return fmt.Errorf("%w: jenis hubungan kerja '%s' tidak dikenal", ErrInvalidEmployment, data.EmploymentType)
+	}
+
+	dates := map[string]time.Time{}
+	for name, value := range map[string]string{
+		"start_date":        data.StartDate,
+		"end_date":          data.EndDate,
+		"contract_end_date": data.ContractEndDate,
+	} {
+		if value == "" {
+			continue
+		}
+		t, err := sitetime.ParseDate(value)
+		if err != nil {
+			return fmt.Errorf("%w: format %s harus YYYY-MM-DD", ErrInvalidEmployment, name)
+		}
+		dates[name] = t
+	}
+
+	start, hasStart := dates["start_date"]
+	if end, ok := dates["end_date"]; ok && hasStart && end.Before(start) {
+		return fmt.Errorf("%w: end_date sebelum start_date", ErrInvalidEmployment)
+	}
+	if contractEnd, ok := dates["contract_end_date"]; ok && hasStart && contractEnd.Before(start) {
+		return fmt.Errorf("%w: contract_end_date sebelum start_date", ErrInvalidEmployment)
+	}
+	if data.EmploymentType == model.EmploymentPermanent && data.ContractEndDate != "" {
+		return fmt.Errorf("%w: karyawan tetap tidak memiliki tanggal akhir kontrak", ErrInvalidEmployment)
+	}
+	return nil
+}
 
 func (s *UserService) CreateUser(data *model.CreateUserRequest) error {
 	fmt.Println(data)
+	if err := validateEmployment(&data.EmploymentData); err != nil {
+		return err
+	}
 	isExist, err := s.UserRepo.IsUserExist(data)
 	if err != nil {
 		return fmt.Errorf("gagal menjalankan method is user")
@@ -62,11 +105,29 @@ func (s *UserService) DeleteUser(id string) error {
 	return nil
 }
 
-func (s *UserService) GetAllUser() ([]model.UserResponse, error) {
-	rows, err := s.UserRepo.GetAllUser()
+func (s *UserService) GetAllUser(filter model.OrgFilter) ([]model.UserResponse, error) {
+	rows, err := s.UserRepo.GetAllUser(filter)
 	if err != nil {
 		return nil, fmt.Errorf("gagal menjalankan service :%w", err)
 	}
 
 	return rows, nil
 }
+
+func (s *UserService) UpdateEmployment(nik string, data *model.EmploymentData) error {
+	if err := validateEmployment(data); err != nil {
+		return err
+	}
+	if err := s.UserRepo.UpdateEmployment(nik, data); err != nil {
+		return fmt.Errorf("gagal menjalankan service update kepegawaian :%w", err)
+	}
+	return nil
+}
+
+func (s *UserService) GetExpiringContracts(days int) ([]model.UserResponse, error) {
+	rows, err := s.UserRepo.GetExpiringContracts(days)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menjalankan service :%w", err)
+	}
+	return rows, nil
+}
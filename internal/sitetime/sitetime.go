@@ -0,0 +1,110 @@
+// Package sitetime: satu zona waktu lokasi pabrik (SITE_TIMEZONE) yang dipakai
+// untuk menentukan batas hari, membaca input tanggal/jam dan menampilkan waktu.
+//
+// Rentang tanggal selalu diubah menjadi instan [awal, akhir) pada zona ini,
+// sehingga hasil laporan tidak bergantung pada zona waktu server aplikasi
+// maupun session Postgres.
+package sitetime
+
+import (
+	"fmt"
+	"os"
+	"time"
+	_ "time/tzdata" // Data zona waktu ikut di-embed, aman untuk image tanpa tzdata
+)
+
+// DefaultZone dipakai jika SITE_TIMEZONE kosong
+const DefaultZone = "Asia/Jakarta"
+
+var location = mustLoad(DefaultZone)
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// LoadFromEnv: membaca SITE_TIMEZONE (nama IANA, misal "Asia/Makassar")
+func LoadFromEnv() error {
+	name := os.Getenv("SITE_TIMEZONE")
+	if name == "" {
+		name = DefaultZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("SITE_TIMEZONE '%s' tidak dikenal: %w", name, err)
+	}
+	location = loc
+	return nil
+}
+
+func Location() *time.Location {
+	return location
+}
+
+// Name: nama zona, dipakai sebagai parameter `AT TIME ZONE` di SQL
+func Name() string {
+	return location.String()
+}
+
+func Now() time.Time {
+	return time.Now().In(location)
+}
+
+// Today: tengah malam hari ini pada zona site
+func Today() time.Time {
+	return StartOfDay(time.Now())
+}
+
+// StartOfDay: tengah malam (zona site) dari hari tempat instan t berada
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+// OnDate: tengah malam zona site untuk tanggal kalender t apa adanya (tanpa
+// konversi zona). Dipakai untuk nilai kolom DATE yang dibaca driver sebagai UTC.
+func OnDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+// DayRange: instan [awal from, awal hari setelah to) pada zona site
+func DayRange(from, to time.Time) (time.Time, time.Time) {
+	return OnDate(from), OnDate(to).AddDate(0, 0, 1)
+}
+
+// ParseDate: "YYYY-MM-DD" -> tengah malam zona site
+func ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, location)
+}
+
+// ParseMonth: "YYYY-MM" -> tanggal 1 tengah malam zona site
+func ParseMonth(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01", value, location)
+}
+
+// dateTimeLayouts: nilai tanpa offset dianggap jam dinding zona site
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+}
+
+// ParseDateTime: menerima RFC3339, "YYYY-MM-DD HH:mm:ss[.SSS] [+0700]" atau "YYYY-MM-DD HH:mm"
+func ParseDateTime(value string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("format waktu '%s' tidak dikenali, gunakan YYYY-MM-DD HH:mm:ss", value)
+}
+
+// FormatDate: tanggal zona site dari instan t
+func FormatDate(t time.Time) string {
+	return t.In(location).Format("2006-01-02")
+}
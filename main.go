@@ -3,11 +3,18 @@ package main
 import (
 	"Steril-App/handler"
 	handlersensor "Steril-App/handler_sensor"
+	"Steril-App/internal/blobstore"
+	"Steril-App/internal/export"
 	"Steril-App/internal/repository"
 	"Steril-App/internal/service"
+	"Steril-App/internal/sitetime"
 	"Steril-App/ws"
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/joho/godotenv"
 	// Hapus import yang ini: "github.com/labstack/echo/middleware"
@@ -18,12 +25,21 @@ import (
 )
 
 func main() {
+	verifyChain := flag.Bool("verify-chain", false, "verifikasi rantai hash fingerlog dan audit_log, lalu keluar")
+	flag.Parse()
+
 	err := godotenv.Load()
 	if err != nil {
 		// Menggunakan %v untuk error
 		fmt.Printf("file .env tidak ada: %v\n", err)
 	}
 
+	// Zona waktu site harus siap sebelum DSN dibuat dan input tanggal diparse
+	if err := sitetime.LoadFromEnv(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	db, err := repository.ConnectDB()
 	if err != nil {
 		fmt.Println("gagal conect db", err)
@@ -38,20 +54,85 @@ func main() {
 	userHandler := handler.NewUserHandler(userService)
 
 	logFingerRepository := repository.NewFingerLogRepostory(db)
-	fingerLogHandler := handler.NewLogFingerHanlere(logFingerRepository)
 
-	wsHandler := ws.NewWebSocketHandler(addFingerRepository, fingerRepository, logFingerRepository)
+	organizationRepository := repository.NewOrganizationRepository(db)
+	organizationHandler := handler.NewOrganizationHandler(organizationRepository)
+
+	shiftRepository := repository.NewShiftRepository(db)
+	shiftService := service.NewShiftService(shiftRepository)
+	shiftHandler := handler.NewShiftHandler(shiftService)
+
+	holidayRepository := repository.NewHolidayRepository(db)
+	holidayService := service.NewHolidayService(holidayRepository)
+	holidayHandler := handler.NewHolidayHandler(holidayService)
+
+	overtimeRepository := repository.NewOvertimeRepository(db)
+	leaveRepository := repository.NewLeaveRepository(db)
+	attendanceService := service.NewAttendanceService(logFingerRepository, userRepository, shiftRepository, overtimeRepository, holidayRepository, leaveRepository)
+	attendanceHandler := handler.NewAttendanceHandler(attendanceService)
+	fingerLogService := service.NewFingerLogService(logFingerRepository)
+	attachmentStore, err := blobstore.FromEnv()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	noteAttachmentRepository := repository.NewNoteAttachmentRepository(db)
+	noteService := service.NewNoteService(logFingerRepository, userRepository, attendanceService, noteAttachmentRepository, attachmentStore)
+	noteAttachmentHandler := handler.NewNoteAttachmentHandler(noteService)
+	fingerLogHandler := handler.NewLogFingerHanlere(logFingerRepository, attendanceService, fingerLogService, noteService)
+	auditRepository := repository.NewAuditRepository(db)
+	auditHandler := handler.NewAuditHandler(auditRepository)
+
+	integrityRepository := repository.NewIntegrityRepository(db)
+	integrityService := service.NewIntegrityService(integrityRepository)
+	integrityHandler := handler.NewIntegrityHandler(integrityService)
+	if *verifyChain {
+		os.Exit(runVerifyChain(integrityService))
+	}
+	go integrityService.Run(context.Background())
+
+	overtimeService := service.NewOvertimeService(overtimeRepository, organizationRepository, attendanceService)
+	overtimeHandler := handler.NewOvertimeHandler(overtimeService)
+
+	leaveService := service.NewLeaveService(leaveRepository, organizationRepository, attendanceService)
+	leaveHandler := handler.NewLeaveHandler(leaveService)
+
+	correctionRepository := repository.NewCorrectionRepository(db)
+	correctionService := service.NewCorrectionService(correctionRepository, logFingerRepository, organizationRepository, userRepository)
+	correctionHandler := handler.NewCorrectionHandler(correctionService)
+
+	absenceRepository := repository.NewAbsenceRepository(db)
+	absenceService := service.NewAbsenceService(absenceRepository, organizationRepository, attendanceService)
+	absenceHandler := handler.NewAbsenceHandler(absenceService)
+	go absenceService.Run(context.Background())
+
+	payrollRepository := repository.NewPayrollRepository(db)
+	payrollService := service.NewPayrollService(payrollRepository, attendanceService)
+	payrollHandler := handler.NewPayrollHandler(payrollService)
+
+	exportHandler := handler.NewExportHandler(attendanceService, logFingerRepository, export.CompanyFromEnv())
+
+	deviceRepository := repository.NewDeviceRepository(db)
+	deviceHandler := handler.NewDeviceHandler(deviceRepository)
+
+	scanService := service.NewScanService(logFingerRepository, shiftRepository, deviceRepository, ws.DebounceSecondsFromEnv())
+	wsHandler := ws.NewWebSocketHandler(addFingerRepository, fingerRepository, logFingerRepository, scanService)
 	// addFingerLog := handlersensor.NewFingerLog(logFingerRepository, fingerRepository)
 
 	// Inisialisasi Echo
 	e := echo.New()
+	e.IPExtractor, err = handler.IPExtractorFromEnv()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	// Pastikan CORS Middleware diaktifkan paling awal
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{
 			http.MethodGet, http.MethodPut, http.MethodPost,
-			http.MethodDelete, http.MethodOptions,
+			http.MethodPatch, http.MethodDelete, http.MethodOptions,
 		},
 		AllowHeaders: []string{
 			echo.HeaderOrigin, echo.HeaderContentType,
@@ -60,24 +141,133 @@ func main() {
 		AllowCredentials: false,
 	}))
 
+	// Aksi berwenang dan semua perubahan yang diaudit: actor dari token, bukan dari body
+	requireActor := handler.RequireActor(handler.ActorTokensFromEnv())
+
 	// Routes
 	e.POST("/create", userHandler.CreateUser)
 	e.DELETE("/delete/:id", userHandler.DeleteUser)
 
 	e.GET("/users", userHandler.GetAllUser)
+	e.GET("/users/contracts/expiring", userHandler.GetExpiringContracts)
+	e.PUT("/users/:nik/employment", userHandler.UpdateEmployment)
+
+	// Struktur organisasi
+	e.POST("/departments", organizationHandler.CreateDepartment)
+	e.GET("/departments", organizationHandler.GetDepartments)
+	e.POST("/lines", organizationHandler.CreateLine)
+	e.GET("/lines", organizationHandler.GetLines)
+	e.POST("/users/:nik/assignments", organizationHandler.AssignUser)
+	e.GET("/users/:nik/assignments", organizationHandler.GetAssignments)
+
+	// Shift & jadwal
+	e.POST("/shifts", shiftHandler.CreateShift)
+	e.GET("/shifts", shiftHandler.GetShifts)
+	e.POST("/schedules/patterns", shiftHandler.CreatePattern)
+	e.GET("/schedules/patterns", shiftHandler.GetPatterns)
+	e.POST("/schedules/assignments", shiftHandler.AssignSchedule)
+	e.GET("/users/:nik/shift", shiftHandler.GetPlannedShift)
+
+	// Kalender hari libur
+	e.POST("/holidays", holidayHandler.CreateHoliday)
+	e.GET("/holidays", holidayHandler.GetHolidays)
+	e.DELETE("/holidays/:id", holidayHandler.DeleteHoliday)
+	e.POST("/holidays/import", holidayHandler.ImportHolidays)
 
 	e.POST("/get", fingerLogHandler.GetFingerLog)
-	e.POST("insert", fingerLogHandler.AddManualFingerLog)
-	e.POST("/remove", fingerLogHandler.DeleteFingerLog)
-	e.POST("/notes", fingerLogHandler.SaveNote)
+	e.GET("/attendance", fingerLogHandler.GetAttendance)
+	e.GET("/attendance/deleted", fingerLogHandler.GetDeletedAttendance)
+	e.DELETE("/attendance/:id", fingerLogHandler.DeleteAttendance, requireActor)
+	e.POST("/attendance/:id/restore", fingerLogHandler.RestoreAttendance, requireActor)
+	e.PATCH("/attendance/:id", fingerLogHandler.CorrectAttendance, requireActor)
+	e.GET("/users/:nik/attendance", fingerLogHandler.GetUserAttendance)
+	e.POST("/summary", attendanceHandler.GetDailySummary)
+	e.POST("/recap", attendanceHandler.GetMonthlyRecap)
+	e.POST("insert", fingerLogHandler.AddManualFingerLog, requireActor)
+	e.POST("/remove", fingerLogHandler.DeleteFingerLog, requireActor)
+	e.POST("/notes", fingerLogHandler.SaveNote, requireActor)
 	e.GET("/notes", fingerLogHandler.GetNotes)
+	e.DELETE("/notes", fingerLogHandler.DeleteNote, requireActor)
+	e.POST("/notes/bulk", fingerLogHandler.BulkNotes, requireActor)
+	e.GET("/notes/history", fingerLogHandler.GetNoteHistory)
+	e.POST("/notes/attachments", noteAttachmentHandler.Upload, requireActor, middleware.BodyLimit(noteAttachmentHandler.UploadBodyLimit()))
+	e.GET("/notes/attachments", noteAttachmentHandler.List)
+	e.GET("/notes/attachments/:id", noteAttachmentHandler.Download)
+	e.DELETE("/notes/attachments/:id", noteAttachmentHandler.Delete, requireActor)
+	e.GET("/audit", auditHandler.GetAuditLog)
+	e.GET("/integrity/verify", integrityHandler.Verify)
+	e.GET("/integrity/checkpoints/:date", integrityHandler.GetCheckpoint)
+	e.GET("/integrity/public-key", integrityHandler.GetPublicKey)
+
+	// Lembur
+	e.POST("/overtime/requests", overtimeHandler.CreateRequest)
+	e.GET("/overtime/requests", overtimeHandler.GetRequests)
+	e.POST("/overtime/requests/:id/approve", overtimeHandler.ApproveRequest, requireActor)
+	e.POST("/overtime/requests/:id/reject", overtimeHandler.RejectRequest, requireActor)
+	e.GET("/overtime/daily", overtimeHandler.GetDaily)
+	e.GET("/overtime/monthly", overtimeHandler.GetMonthly)
+
+	// Cuti & izin
+	e.GET("/leave/types", leaveHandler.GetLeaveTypes)
+	e.POST("/leave/requests", leaveHandler.CreateRequest)
+	e.GET("/leave/requests", leaveHandler.GetRequests)
+	e.POST("/leave/requests/:id/approve", leaveHandler.ApproveRequest, requireActor)
+	e.POST("/leave/requests/:id/reject", leaveHandler.RejectRequest, requireActor)
+	e.GET("/users/:nik/leave-balance", leaveHandler.GetBalance)
+	e.PUT("/users/:nik/leave-balance", leaveHandler.SetBalance, requireActor)
+
+	// Pengajuan koreksi absensi (disetujui HR)
+	e.POST("/corrections", correctionHandler.CreateRequest)
+	e.GET("/corrections", correctionHandler.GetRequests)
+	e.GET("/corrections/:id", correctionHandler.GetRequest)
+	e.POST("/corrections/:id/approve", correctionHandler.ApproveRequest, requireActor)
+	e.POST("/corrections/:id/reject", correctionHandler.RejectRequest, requireActor)
+	e.POST("/corrections/:id/comments", correctionHandler.AddComment)
+
+	// Ketidakhadiran
+	e.GET("/absences", absenceHandler.GetAbsences)
+	e.POST("/absences/detect", absenceHandler.DetectAbsences)
+
+	// Export laporan (format=csv|xlsx|pdf)
+	e.GET("/export/log", exportHandler.ExportLog)
+	e.GET("/export/summary", exportHandler.ExportSummary)
+	e.GET("/export/recap", exportHandler.ExportRecap)
+	e.GET("/reports/matrix", exportHandler.GetMatrix)
+
+	// Payroll: template export dan total per periode
+	e.GET("/payroll/templates", payrollHandler.GetTemplates)
+	e.GET("/payroll/templates/:name", payrollHandler.GetTemplate)
+	e.PUT("/payroll/templates/:name", payrollHandler.SaveTemplate)
+	e.DELETE("/payroll/templates/:name", payrollHandler.DeleteTemplate)
+	e.GET("/payroll/templates/:name/employee-ids", payrollHandler.GetEmployeeIDs)
+	e.PUT("/payroll/templates/:name/employee-ids", payrollHandler.SaveEmployeeIDs)
+	e.GET("/payroll/totals", payrollHandler.GetTotals)
+	e.GET("/export/payroll", payrollHandler.Export)
 
 	//Sensor
 	e.GET("/ws", wsHandler.HandleWebSocket)
 	e.GET("/scan", handlersensor.ScanRegisteredFinger)
 	e.POST("/add", handlersensor.AddFingerByID)
 	e.POST("/del", handlersensor.DeleteFingerByID)
+	e.POST("/devices", deviceHandler.SaveDevice)
+	e.GET("/devices", deviceHandler.GetDevices)
 
 	// Jalankan server
 	e.Logger.Fatal(e.Start(":8083"))
 }
+
+// runVerifyChain: mode `-verify-chain`, mencetak laporan JSON. Exit code 1 jika
+// ada rantai atau checkpoint yang rusak, 2 jika verifikasi gagal dijalankan.
+func runVerifyChain(integrity *service.IntegrityService) int {
+	report, err := integrity.Verify()
+	if err != nil {
+		fmt.Println("gagal verifikasi rantai:", err)
+		return 2
+	}
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
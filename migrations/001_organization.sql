@@ -0,0 +1,30 @@
+-- Struktur organisasi: departemen, line produksi dan penugasan user.
+CREATE TABLE IF NOT EXISTS departments (
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(20)  NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS production_lines (
+    id            SERIAL PRIMARY KEY,
+    department_id INT          NOT NULL REFERENCES departments (id),
+    code          VARCHAR(20)  NOT NULL UNIQUE,
+    name          VARCHAR(100) NOT NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Satu baris = satu periode penugasan user. effective_to NULL berarti masih berlaku.
+CREATE TABLE IF NOT EXISTS user_assignments (
+    id             SERIAL PRIMARY KEY,
+    nik            VARCHAR(50) NOT NULL,
+    department_id  INT         NOT NULL REFERENCES departments (id),
+    line_id        INT         REFERENCES production_lines (id),
+    supervisor_nik VARCHAR(50),
+    effective_from DATE        NOT NULL,
+    effective_to   DATE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_assignments_nik ON user_assignments (nik, effective_from);
+CREATE INDEX IF NOT EXISTS idx_user_assignments_supervisor ON user_assignments (supervisor_nik);
@@ -0,0 +1,9 @@
+-- Atribut kepegawaian pada tabel users.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS employment_type   VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS job_title         VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS start_date        DATE,
+    ADD COLUMN IF NOT EXISTS end_date          DATE,
+    ADD COLUMN IF NOT EXISTS contract_end_date DATE;
+
+CREATE INDEX IF NOT EXISTS idx_users_contract_end_date ON users (contract_end_date);
@@ -0,0 +1,41 @@
+-- Template shift dan pola jadwal bergilir.
+CREATE TABLE IF NOT EXISTS shifts (
+    id            SERIAL PRIMARY KEY,
+    code          VARCHAR(20)  NOT NULL UNIQUE,
+    name          VARCHAR(100) NOT NULL,
+    start_time    TIME         NOT NULL,
+    end_time      TIME         NOT NULL, -- end_time <= start_time berarti shift melewati tengah malam
+    grace_minutes INT          NOT NULL DEFAULT 0,
+    break_minutes INT          NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS schedule_patterns (
+    id         SERIAL PRIMARY KEY,
+    code       VARCHAR(20)  NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Urutan hari dalam satu siklus pola. shift_id NULL = libur.
+CREATE TABLE IF NOT EXISTS schedule_pattern_days (
+    pattern_id INT NOT NULL REFERENCES schedule_patterns (id) ON DELETE CASCADE,
+    day_index  INT NOT NULL,
+    shift_id   INT REFERENCES shifts (id),
+    PRIMARY KEY (pattern_id, day_index)
+);
+
+-- Jadwal dipasang ke satu user (nik) atau satu line. start_date adalah hari ke-0 siklus.
+CREATE TABLE IF NOT EXISTS schedule_assignments (
+    id         SERIAL PRIMARY KEY,
+    pattern_id INT         NOT NULL REFERENCES schedule_patterns (id),
+    nik        VARCHAR(50),
+    line_id    INT REFERENCES production_lines (id),
+    start_date DATE        NOT NULL,
+    end_date   DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((nik IS NULL) <> (line_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_assignments_nik ON schedule_assignments (nik);
+CREATE INDEX IF NOT EXISTS idx_schedule_assignments_line ON schedule_assignments (line_id);
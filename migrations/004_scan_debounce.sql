@@ -0,0 +1,17 @@
+-- Perangkat scanner dan penanda scan ganda (debounce).
+CREATE TABLE IF NOT EXISTS scan_devices (
+    code             VARCHAR(50)  PRIMARY KEY,
+    name             VARCHAR(100) NOT NULL,
+    debounce_seconds INT, -- NULL = pakai SCAN_DEBOUNCE_SECONDS global
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE fingerlog
+    ADD COLUMN IF NOT EXISTS device_code  VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS is_duplicate BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_fingerlog_nik_timestamp ON fingerlog (nik, timestamp);
+
+-- Data mentah tetap di fingerlog; semua laporan membaca dari view ini.
+CREATE OR REPLACE VIEW fingerlog_clean AS
+    SELECT * FROM fingerlog WHERE NOT is_duplicate;
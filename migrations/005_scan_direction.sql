@@ -0,0 +1,10 @@
+-- Arah scan (masuk/keluar) per log dan arah default per perangkat.
+ALTER TABLE scan_devices
+    ADD COLUMN IF NOT EXISTS direction VARCHAR(3); -- 'IN', 'OUT' atau NULL (dua arah)
+
+ALTER TABLE fingerlog
+    ADD COLUMN IF NOT EXISTS direction        VARCHAR(3),  -- 'IN' / 'OUT'
+    ADD COLUMN IF NOT EXISTS direction_source VARCHAR(10); -- 'sensor', 'device', 'inferred', 'manual'
+
+CREATE OR REPLACE VIEW fingerlog_clean AS
+    SELECT * FROM fingerlog WHERE NOT is_duplicate;
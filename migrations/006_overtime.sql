@@ -0,0 +1,20 @@
+-- Pengajuan lembur yang disetujui atasan sebelum dijalankan.
+CREATE TABLE IF NOT EXISTS overtime_requests (
+    id              SERIAL PRIMARY KEY,
+    nik             VARCHAR(50)  NOT NULL,
+    date            DATE         NOT NULL,
+    planned_minutes INT          NOT NULL CHECK (planned_minutes > 0),
+    reason          TEXT         NOT NULL,
+    status          VARCHAR(20)  NOT NULL DEFAULT 'submitted', -- submitted, approved, rejected
+    requested_by    VARCHAR(50)  NOT NULL,
+    decided_by      VARCHAR(50),
+    decided_at      TIMESTAMPTZ,
+    decision_note   TEXT,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_overtime_requests_date ON overtime_requests (date, nik);
+
+-- Hanya satu pengajuan aktif (belum ditolak) per karyawan per tanggal
+CREATE UNIQUE INDEX IF NOT EXISTS uq_overtime_requests_active
+    ON overtime_requests (nik, date) WHERE status <> 'rejected';
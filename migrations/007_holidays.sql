@@ -0,0 +1,17 @@
+-- Lokasi pabrik (site) per departemen, dipakai untuk membatasi hari libur.
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS site VARCHAR(50);
+
+-- Kalender hari libur. department_id dan site NULL berarti berlaku untuk semua.
+CREATE TABLE IF NOT EXISTS holidays (
+    id            SERIAL PRIMARY KEY,
+    date          DATE         NOT NULL,
+    name          VARCHAR(200) NOT NULL,
+    kind          VARCHAR(20)  NOT NULL DEFAULT 'public', -- public, company
+    department_id INT          REFERENCES departments (id),
+    site          VARCHAR(50),
+    source        VARCHAR(20)  NOT NULL DEFAULT 'manual', -- manual, ics
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_holidays_scope
+    ON holidays (date, COALESCE(department_id, 0), COALESCE(site, ''));
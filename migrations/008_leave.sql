@@ -0,0 +1,28 @@
+-- Pengajuan cuti/izin dengan rentang tanggal dan persetujuan atasan.
+CREATE TABLE IF NOT EXISTS leave_requests (
+    id            SERIAL PRIMARY KEY,
+    nik           VARCHAR(50) NOT NULL,
+    leave_type    VARCHAR(20) NOT NULL, -- annual, sick, permit, maternity, unpaid
+    start_date    DATE        NOT NULL,
+    end_date      DATE        NOT NULL CHECK (end_date >= start_date),
+    days          INT         NOT NULL, -- Hari kerja yang dipotong (di luar libur/off)
+    reason        TEXT        NOT NULL,
+    status        VARCHAR(20) NOT NULL DEFAULT 'submitted', -- submitted, approved, rejected
+    requested_by  VARCHAR(50) NOT NULL,
+    decided_by    VARCHAR(50),
+    decided_at    TIMESTAMPTZ,
+    decision_note TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_requests_nik ON leave_requests (nik, start_date);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_period ON leave_requests (start_date, end_date) WHERE status = 'approved';
+
+-- Hak cuti tahunan per karyawan. Tanpa baris di sini dipakai hak default.
+CREATE TABLE IF NOT EXISTS leave_balances (
+    nik           VARCHAR(50) NOT NULL,
+    year          INT         NOT NULL,
+    entitled_days INT         NOT NULL CHECK (entitled_days >= 0),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (nik, year)
+);
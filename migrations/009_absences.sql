@@ -0,0 +1,15 @@
+-- Ketidakhadiran yang terdeteksi otomatis setelah shift berakhir.
+CREATE TABLE IF NOT EXISTS absences (
+    id             SERIAL PRIMARY KEY,
+    nik            VARCHAR(50) NOT NULL,
+    date           DATE        NOT NULL,
+    shift_code     VARCHAR(20) NOT NULL,
+    shift_start    TIMESTAMPTZ NOT NULL,
+    shift_end      TIMESTAMPTZ NOT NULL,
+    supervisor_nik VARCHAR(50),
+    detected_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    notified_at    TIMESTAMPTZ,
+    UNIQUE (nik, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_absences_date ON absences (date);
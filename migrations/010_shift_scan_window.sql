@@ -0,0 +1,6 @@
+-- Jendela scan per shift: scan yang jatuh di [mulai - before, selesai + after]
+-- dihitung untuk tanggal kerja shift tsb, meski tanggal kalendernya berbeda
+-- (misal scan pulang 07:05 untuk shift malam 23:00-07:00 kemarin).
+ALTER TABLE shifts
+    ADD COLUMN IF NOT EXISTS window_before_minutes INT NOT NULL DEFAULT 180,
+    ADD COLUMN IF NOT EXISTS window_after_minutes  INT NOT NULL DEFAULT 360;
@@ -0,0 +1,33 @@
+-- Jejak audit perubahan absensi manual (insert/hapus scan, ubah catatan).
+-- Baris tidak boleh diubah maupun dihapus: dijaga trigger di bawah.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id         BIGSERIAL PRIMARY KEY,
+    actor      VARCHAR(50)  NOT NULL,
+    action     VARCHAR(50)  NOT NULL, -- 'fingerlog.insert', 'fingerlog.delete', 'note.save'
+    nik        VARCHAR(50)  NOT NULL, -- Karyawan yang datanya berubah
+    date       DATE         NOT NULL, -- Tanggal data yang berubah (zona site)
+    before     JSONB,                 -- NULL untuk insert
+    after      JSONB,                 -- NULL untuk hapus
+    reason     TEXT         NOT NULL CHECK (btrim(reason) <> ''),
+    client_ip  VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_nik_date_idx ON audit_log (nik, date);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor, created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log bersifat append-only, % tidak diizinkan', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log;
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_immutable();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_immutable();
@@ -0,0 +1,116 @@
+-- Rantai hash (tamper-evident) untuk fingerlog dan audit_log.
+-- Setiap baris baru menyimpan hash SHA-256 atas isinya dan hash baris sebelumnya
+-- pada rantai yang sama. Baris lama (sebelum migrasi ini) tidak ikut rantai.
+--
+-- Isi kanonik: prev_hash || '|' || seq || '|' || field..., dengan setiap field
+-- ditulis sebagai "<panjang byte>:<nilai>" (lihat chain_field). Aplikasi
+-- (service.IntegrityService) menghitung ulang dengan format yang sama.
+CREATE TABLE IF NOT EXISTS chain_heads (
+    chain VARCHAR(20) PRIMARY KEY,
+    seq   BIGINT      NOT NULL,
+    hash  CHAR(64)    NOT NULL
+);
+
+ALTER TABLE fingerlog
+    ADD COLUMN IF NOT EXISTS chain_seq  BIGINT,
+    ADD COLUMN IF NOT EXISTS prev_hash  CHAR(64),
+    ADD COLUMN IF NOT EXISTS hash       CHAR(64),
+    ADD COLUMN IF NOT EXISTS chained_at TIMESTAMPTZ;
+
+ALTER TABLE audit_log
+    ADD COLUMN IF NOT EXISTS chain_seq  BIGINT,
+    ADD COLUMN IF NOT EXISTS prev_hash  CHAR(64),
+    ADD COLUMN IF NOT EXISTS hash       CHAR(64),
+    ADD COLUMN IF NOT EXISTS chained_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS fingerlog_chain_seq_idx ON fingerlog (chain_seq);
+CREATE UNIQUE INDEX IF NOT EXISTS audit_log_chain_seq_idx ON audit_log (chain_seq);
+
+-- fingerlog_clean ikut membawa kolom baru
+CREATE OR REPLACE VIEW fingerlog_clean AS
+    SELECT * FROM fingerlog WHERE NOT is_duplicate;
+
+CREATE OR REPLACE FUNCTION chain_field(value TEXT) RETURNS TEXT AS $$
+    SELECT octet_length(COALESCE(value, '')) || ':' || COALESCE(value, '')
+$$ LANGUAGE sql IMMUTABLE;
+
+-- chain_micros: waktu sebagai mikrodetik sejak epoch (time.UnixMicro di Go)
+CREATE OR REPLACE FUNCTION chain_micros(value TIMESTAMPTZ) RETURNS TEXT AS $$
+    SELECT ((extract(epoch FROM value) * 1000000)::bigint)::text
+$$ LANGUAGE sql IMMUTABLE;
+
+-- chain_append: mengambil nomor urut dan hash berikutnya. Baris chain_heads
+-- dikunci sampai transaksi selesai, sehingga insert paralel tidak bercabang
+-- dan rollback ikut membatalkan kemajuan head.
+CREATE OR REPLACE FUNCTION chain_append(p_chain TEXT, p_content TEXT,
+    OUT o_seq BIGINT, OUT o_prev CHAR(64), OUT o_hash CHAR(64)) AS $$
+BEGIN
+    INSERT INTO chain_heads (chain, seq, hash) VALUES (p_chain, 0, repeat('0', 64))
+        ON CONFLICT (chain) DO NOTHING;
+    SELECT h.seq + 1, h.hash INTO o_seq, o_prev
+        FROM chain_heads h WHERE h.chain = p_chain FOR UPDATE;
+    o_hash := encode(sha256(convert_to(o_prev || '|' || o_seq || '|' || p_content, 'UTF8')), 'hex');
+    UPDATE chain_heads SET seq = o_seq, hash = o_hash WHERE chain = p_chain;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION fingerlog_chain() RETURNS trigger AS $$
+DECLARE
+    link RECORD;
+BEGIN
+    SELECT * INTO link FROM chain_append('fingerlog',
+        chain_field(NEW.nik) ||
+        chain_field(chain_micros(NEW.timestamp)) ||
+        chain_field(NEW.device_code) ||
+        chain_field(NEW.direction) ||
+        chain_field(NEW.direction_source) ||
+        chain_field(NEW.is_duplicate::text));
+    NEW.chain_seq  := link.o_seq;
+    NEW.prev_hash  := link.o_prev;
+    NEW.hash       := link.o_hash;
+    NEW.chained_at := clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS fingerlog_chain ON fingerlog;
+CREATE TRIGGER fingerlog_chain
+    BEFORE INSERT ON fingerlog
+    FOR EACH ROW EXECUTE FUNCTION fingerlog_chain();
+
+CREATE OR REPLACE FUNCTION audit_log_chain() RETURNS trigger AS $$
+DECLARE
+    link RECORD;
+BEGIN
+    SELECT * INTO link FROM chain_append('audit_log',
+        chain_field(NEW.actor) ||
+        chain_field(NEW.action) ||
+        chain_field(NEW.nik) ||
+        chain_field(NEW.date::text) ||
+        chain_field(NEW.before::text) ||
+        chain_field(NEW.after::text) ||
+        chain_field(NEW.reason) ||
+        chain_field(NEW.client_ip) ||
+        chain_field(chain_micros(NEW.created_at)));
+    NEW.chain_seq  := link.o_seq;
+    NEW.prev_hash  := link.o_prev;
+    NEW.hash       := link.o_hash;
+    NEW.chained_at := clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_chain ON audit_log;
+CREATE TRIGGER audit_log_chain
+    BEFORE INSERT ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_chain();
+
+-- Checkpoint harian: head kedua rantai pada akhir hari (zona site), ditandatangani
+-- ed25519. payload disimpan apa adanya karena tanda tangan dihitung atas byte tsb.
+CREATE TABLE IF NOT EXISTS integrity_checkpoints (
+    date       DATE        PRIMARY KEY,
+    payload    TEXT        NOT NULL,
+    signature  TEXT        NOT NULL, -- base64
+    public_key TEXT        NOT NULL, -- base64, kunci yang dipakai saat tanda tangan
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
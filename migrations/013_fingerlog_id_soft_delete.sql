@@ -0,0 +1,15 @@
+-- ID stabil untuk setiap log dan hapus lunak (soft delete).
+-- Log tidak pernah dihapus fisik agar rantai hash tetap utuh; koreksi jam
+-- menandai log lama terhapus lalu menambah log baru (replaced_by menunjuk ke sana).
+ALTER TABLE fingerlog
+    ADD COLUMN IF NOT EXISTS id            BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS deleted_at    TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_by    VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS delete_reason TEXT,
+    ADD COLUMN IF NOT EXISTS replaced_by   BIGINT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS fingerlog_id_idx ON fingerlog (id);
+
+-- Log terhapus tidak ikut laporan
+CREATE OR REPLACE VIEW fingerlog_clean AS
+    SELECT * FROM fingerlog WHERE NOT is_duplicate AND deleted_at IS NULL;
@@ -0,0 +1,38 @@
+-- Pengajuan koreksi absensi manual (tambah scan yang lupa / hapus scan) oleh
+-- line leader. Diarahkan ke atasan karyawan atau HR; hanya HR yang menyetujui,
+-- dan persetujuan langsung diterapkan ke fingerlog.
+CREATE TABLE IF NOT EXISTS correction_requests (
+    id             SERIAL PRIMARY KEY,
+    nik            VARCHAR(50)  NOT NULL,
+    action         VARCHAR(10)  NOT NULL CHECK (action IN ('add', 'delete')),
+    timestamp      TIMESTAMPTZ,           -- action 'add': jam scan yang ditambahkan
+    direction      VARCHAR(3),            -- action 'add', opsional
+    log_id         BIGINT,                -- action 'delete': ID fingerlog
+    reason         TEXT         NOT NULL,
+    status         VARCHAR(20)  NOT NULL DEFAULT 'submitted', -- submitted, approved, rejected
+    requested_by   VARCHAR(50)  NOT NULL,
+    assigned_to    VARCHAR(50)  NOT NULL, -- NIK atasan, atau 'HR' jika tidak ada atasan
+    decided_by     VARCHAR(50),
+    decided_at     TIMESTAMPTZ,
+    decision_note  TEXT,
+    applied_log_id BIGINT,                -- action 'add': ID fingerlog hasil persetujuan
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CHECK ((action = 'add' AND timestamp IS NOT NULL) OR (action = 'delete' AND log_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_correction_requests_assigned ON correction_requests (assigned_to, status);
+CREATE INDEX IF NOT EXISTS idx_correction_requests_nik ON correction_requests (nik, created_at);
+
+-- Satu pengajuan hapus yang masih menunggu per log
+CREATE UNIQUE INDEX IF NOT EXISTS uq_correction_requests_pending_delete
+    ON correction_requests (log_id) WHERE status = 'submitted';
+
+CREATE TABLE IF NOT EXISTS correction_comments (
+    id         SERIAL PRIMARY KEY,
+    request_id INT          NOT NULL REFERENCES correction_requests (id),
+    author     VARCHAR(50)  NOT NULL,
+    body       TEXT         NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_correction_comments_request ON correction_comments (request_id, created_at);
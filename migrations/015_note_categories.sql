@@ -0,0 +1,29 @@
+-- Catatan harian berkategori. detaillog tetap menyimpan isi terkini per NIK
+-- per tanggal; setiap perubahan disimpan sebagai revisi di note_revisions.
+ALTER TABLE detaillog
+    ADD COLUMN IF NOT EXISTS category   VARCHAR(20) NOT NULL DEFAULT 'other',
+    ADD COLUMN IF NOT EXISTS updated_by VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+ALTER TABLE detaillog DROP CONSTRAINT IF EXISTS detaillog_category_check;
+ALTER TABLE detaillog ADD CONSTRAINT detaillog_category_check
+    CHECK (category IN ('sick', 'permit', 'duty_outside', 'late_reason', 'other'));
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id         BIGSERIAL PRIMARY KEY,
+    nik        VARCHAR(50)  NOT NULL,
+    date       DATE         NOT NULL,
+    category   VARCHAR(20)  NOT NULL,
+    detail     TEXT         NOT NULL,
+    author     VARCHAR(50)  NOT NULL,
+    reason     TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_revisions_nik_date ON note_revisions (nik, date, created_at);
+
+-- Catatan lama menjadi revisi pertama
+INSERT INTO note_revisions (nik, date, category, detail, author, reason)
+SELECT d.nik, d.date::date, d.category, d.detail, 'migrasi', 'Catatan sebelum riwayat revisi'
+FROM detaillog d
+WHERE NOT EXISTS (SELECT 1 FROM note_revisions r WHERE r.nik = d.nik AND r.date = d.date::date);
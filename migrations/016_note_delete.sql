@@ -0,0 +1,3 @@
+-- Catatan harian dapat dihapus; penghapusan dicatat sebagai revisi terakhir
+-- (isi terakhir dengan deleted = TRUE) agar riwayatnya tidak hilang.
+ALTER TABLE note_revisions ADD COLUMN IF NOT EXISTS deleted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,16 @@
+-- Lampiran catatan harian (surat dokter, surat izin). Isi file disimpan di
+-- blob store (NOTE_ATTACHMENT_DIR), tabel ini hanya metadata dan checksum.
+CREATE TABLE IF NOT EXISTS note_attachments (
+    id           SERIAL PRIMARY KEY,
+    nik          VARCHAR(50)  NOT NULL,
+    date         DATE         NOT NULL,
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes   BIGINT       NOT NULL,
+    sha256       CHAR(64)     NOT NULL,
+    storage_key  VARCHAR(255) NOT NULL UNIQUE,
+    uploaded_by  VARCHAR(50)  NOT NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_attachments_nik_date ON note_attachments (nik, date);
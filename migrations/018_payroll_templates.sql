@@ -0,0 +1,46 @@
+-- Template export payroll: tata letak kolom, header, format angka/tanggal dan
+-- sumber ID karyawan. Definisi disimpan sebagai JSON (model.PayrollTemplate).
+CREATE TABLE IF NOT EXISTS payroll_templates (
+    name       VARCHAR(50) PRIMARY KEY,
+    definition JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- ID karyawan di sistem payroll, per template (identifier = 'mapped')
+CREATE TABLE IF NOT EXISTS payroll_employee_ids (
+    template    VARCHAR(50) NOT NULL REFERENCES payroll_templates (name) ON DELETE CASCADE,
+    nik         VARCHAR(50) NOT NULL,
+    external_id VARCHAR(50) NOT NULL,
+    PRIMARY KEY (template, nik)
+);
+
+INSERT INTO payroll_templates (name, definition) VALUES ('default', '{
+  "name": "default",
+  "description": "Total absensi per karyawan, CSV standar",
+  "delimiter": ",",
+  "decimal_separator": ".",
+  "decimals": 2,
+  "date_format": "YYYY-MM-DD",
+  "identifier": "nik",
+  "unmapped": "error",
+  "columns": [
+    {"header": "NIK", "field": "employee_id"},
+    {"header": "Nama", "field": "full_name"},
+    {"header": "Departemen", "field": "department"},
+    {"header": "Dari", "field": "period_from"},
+    {"header": "Sampai", "field": "period_to"},
+    {"header": "Hari Kerja", "field": "workdays"},
+    {"header": "Hadir", "field": "days_present"},
+    {"header": "Terlambat (mnt)", "field": "late_minutes"},
+    {"header": "Mangkir", "field": "absences"},
+    {"header": "Cuti Tahunan", "field": "leave:annual"},
+    {"header": "Sakit", "field": "leave:sick"},
+    {"header": "Izin", "field": "leave:permit"},
+    {"header": "Cuti Tidak Dibayar", "field": "leave:unpaid"},
+    {"header": "Lembur 1.5x (jam)", "field": "overtime_x1_5_hours"},
+    {"header": "Lembur 2x (jam)", "field": "overtime_x2_hours"},
+    {"header": "Lembur 3x (jam)", "field": "overtime_x3_hours"},
+    {"header": "Lembur 4x (jam)", "field": "overtime_x4_hours"},
+    {"header": "Jam Lembur Tertimbang", "field": "overtime_weighted_hours"}
+  ]
+}') ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,14 @@
+-- fingerlog_clean dengan kolom eksplisit. Versi sebelumnya (SELECT *) membekukan
+-- daftar kolom saat view dibuat, sehingga setiap ALTER TABLE fingerlog harus
+-- diikuti pembuatan ulang view. Laporan hanya membutuhkan kolom di bawah; kolom
+-- rantai hash dan hapus lunak sengaja tidak ikut.
+BEGIN;
+
+DROP VIEW IF EXISTS fingerlog_clean;
+
+CREATE VIEW fingerlog_clean AS
+    SELECT id, nik, timestamp, device_code, direction, direction_source
+    FROM fingerlog
+    WHERE NOT is_duplicate AND deleted_at IS NULL;
+
+COMMIT;
@@ -2,24 +2,65 @@ package model
 
 import "time"
 
+// Jenis hubungan kerja
+const (
+	EmploymentPermanent   = "permanent"
+	EmploymentContract    = "contract"
+	EmploymentOutsourcing = "outsourcing"
+)
+
 type User struct {
-	ID        int       `json:"id"`
-	NIK       string    `json:"nik"`
-	FullName  string    `json:"full_name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              int        `json:"id"`
+	NIK             string     `json:"nik"`
+	FullName        string     `json:"full_name"`
+	EmploymentType  string     `json:"employment_type"`
+	JobTitle        string     `json:"job_title"`
+	StartDate       *time.Time `json:"start_date"`
+	EndDate         *time.Time `json:"end_date"`
+	ContractEndDate *time.Time `json:"contract_end_date"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 type UserResponse struct {
-	ID       int    `json:"id"`
-	NIK      string `json:"nik"`
-	FullName string `json:"full_name"`
+	ID              int        `json:"id"`
+	NIK             string     `json:"nik"`
+	FullName        string     `json:"full_name"`
+	EmploymentType  string     `json:"employment_type"`
+	JobTitle        string     `json:"job_title"`
+	StartDate       *time.Time `json:"start_date"`
+	EndDate         *time.Time `json:"end_date"`
+	ContractEndDate *time.Time `json:"contract_end_date"`
+	DepartmentName  string     `json:"department_name"`
+	LineName        string     `json:"line_name"`
+	SupervisorNIK   string     `json:"supervisor_nik"`
+}
+
+// EmploymentData: atribut kepegawaian, tanggal dalam format "YYYY-MM-DD" (opsional)
+type EmploymentData struct {
+	EmploymentType  string `json:"employment_type"`
+	JobTitle        string `json:"job_title"`
+	StartDate       string `json:"start_date"`
+	EndDate         string `json:"end_date"`
+	ContractEndDate string `json:"contract_end_date"`
 }
 
 type CreateUserRequest struct {
 	NIK      string `json:"nik"`
 	FullName string `json:"full_name"`
+	EmploymentData
 }
 
 type DeleteUserRequest struct {
 	ID string `json:"id"`
 }
+
+// Employee: identitas ringkas karyawan untuk laporan absensi
+type Employee struct {
+	NIK            string     `json:"nik"`
+	FullName       string     `json:"full_name"`
+	DepartmentName string     `json:"department_name"`
+	DepartmentID   int        `json:"-"`
+	Site           string     `json:"-"`
+	StartDate      *time.Time `json:"-"`
+	EndDate        *time.Time `json:"-"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Absence: karyawan terjadwal yang tidak scan sama sekali dan tidak memiliki
+// cuti/catatan sampai shift-nya berakhir
+type Absence struct {
+	ID             int        `json:"id"`
+	NIK            string     `json:"nik"`
+	FullName       string     `json:"full_name"`
+	DepartmentName string     `json:"department_name"`
+	Date           time.Time  `json:"date"`
+	ShiftCode      string     `json:"shift_code"`
+	ShiftStart     time.Time  `json:"shift_start"`
+	ShiftEnd       time.Time  `json:"shift_end"`
+	SupervisorNIK  string     `json:"supervisor_nik,omitempty"`
+	DetectedAt     time.Time  `json:"detected_at"`
+	NotifiedAt     *time.Time `json:"notified_at,omitempty"`
+}
+
+type AbsenceFilter struct {
+	From string `query:"from"` // Format: "YYYY-MM-DD"
+	To   string `query:"to"`
+	OrgFilter
+}
+
+// AbsenceNotification: payload webhook, satu per atasan per tanggal
+type AbsenceNotification struct {
+	Event         string    `json:"event"`
+	SupervisorNIK string    `json:"supervisor_nik"`
+	Date          string    `json:"date"`
+	Absences      []Absence `json:"absences"`
+}
@@ -0,0 +1,109 @@
+package model
+
+import "time"
+
+// Status harian absensi
+const (
+	StatusPresent    = "present"
+	StatusLate       = "late"
+	StatusIncomplete = "incomplete"
+	StatusAbsent     = "absent"
+	StatusLeave      = "leave" // Tidak ada scan, tetapi ada catatan (sakit/izin/cuti)
+	StatusOff        = "off"
+)
+
+// DailySummary: ringkasan absensi satu karyawan pada satu hari. Durasi dalam menit.
+type DailySummary struct {
+	NIK               string     `json:"nik"`
+	FullName          string     `json:"full_name"`
+	DepartmentName    string     `json:"department_name"`
+	Date              string     `json:"date"`
+	ShiftCode         string     `json:"shift_code"`
+	ShiftStart        *time.Time `json:"shift_start"`
+	ShiftEnd          *time.Time `json:"shift_end"`
+	CheckIn           *time.Time `json:"check_in"`
+	CheckOut          *time.Time `json:"check_out"`
+	ScanCount         int        `json:"scan_count"`
+	WorkedMinutes     int        `json:"worked_minutes"`
+	BreakMinutes      int        `json:"break_minutes"`
+	LateMinutes       int        `json:"late_minutes"`
+	EarlyLeaveMinutes int        `json:"early_leave_minutes"`
+	OvertimeMinutes   int        `json:"overtime_minutes"`          // Lembur aktual dari log
+	ApprovedOvertime  int        `json:"approved_overtime_minutes"` // Lembur yang disetujui atasan
+	PaidOvertime      int        `json:"paid_overtime_minutes"`     // Aktual, dibatasi persetujuan
+	IsRestDay         bool       `json:"is_rest_day"`
+	Holiday           string     `json:"holiday,omitempty"`
+	Status            string     `json:"status"`
+	LeaveType         string     `json:"leave_type,omitempty"`
+	Note              string     `json:"note,omitempty"`
+	NoteCategory      string     `json:"note_category,omitempty"`
+}
+
+type DailySummaryRequest struct {
+	Date string `json:"date"`
+	OrgFilter
+}
+
+// MonthlyRecap: rekap absensi satu karyawan dalam satu bulan
+type MonthlyRecap struct {
+	NIK            string  `json:"nik"`
+	FullName       string  `json:"full_name"`
+	DepartmentName string  `json:"department_name"`
+	Month          string  `json:"month"`
+	ScheduledDays  int     `json:"scheduled_days"`
+	DaysPresent    int     `json:"days_present"`
+	LateCount      int     `json:"late_count"`
+	LateMinutes    int     `json:"late_minutes"`
+	IncompleteDays int     `json:"incomplete_days"`
+	Absences       int     `json:"absences"`
+	LeaveDays      int     `json:"leave_days"`
+	OvertimeHours  float64 `json:"overtime_hours"` // Lembur dibayar (sudah dibatasi persetujuan)
+	WorkedHours    float64 `json:"worked_hours"`
+}
+
+type MonthlyRecapRequest struct {
+	Month string `json:"month"` // Format: "YYYY-MM"
+	OrgFilter
+}
+
+// ExportRequest: parameter query untuk endpoint /export/*
+type ExportRequest struct {
+	Format string `query:"format"` // csv, xlsx atau pdf
+	Date   string `query:"date"`
+	From   string `query:"from"`
+	To     string `query:"to"`
+	Month  string `query:"month"`
+	OrgFilter
+}
+
+// Kode status pada lembar absensi (matriks karyawan x tanggal)
+const (
+	CodeHadir = "H"
+	CodeSakit = "S"
+	CodeIzin  = "I"
+	CodeAlpha = "A"
+	CodeCuti  = "C"
+	CodeLibur = "L"
+)
+
+type MatrixCell struct {
+	Date     string     `json:"date"`
+	Code     string     `json:"code"`
+	CheckIn  *time.Time `json:"check_in,omitempty"`
+	CheckOut *time.Time `json:"check_out,omitempty"`
+	Text     string     `json:"text"` // Isi sel pada lembar cetak: jam masuk-pulang atau kode
+}
+
+type MatrixRow struct {
+	NIK            string         `json:"nik"`
+	FullName       string         `json:"full_name"`
+	DepartmentName string         `json:"department_name"`
+	Cells          []MatrixCell   `json:"cells"`
+	Totals         map[string]int `json:"totals"` // Jumlah per kode status
+}
+
+type AttendanceMatrix struct {
+	Month string      `json:"month"`
+	Dates []string    `json:"dates"`
+	Rows  []MatrixRow `json:"rows"`
+}
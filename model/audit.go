@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Aksi yang dicatat di audit_log
+const (
+	AuditFingerLogInsert  = "fingerlog.insert"
+	AuditFingerLogDelete  = "fingerlog.delete"
+	AuditFingerLogCorrect = "fingerlog.correct"
+	AuditFingerLogRestore = "fingerlog.restore"
+	AuditNoteSave         = "note.save"
+	AuditNoteDelete       = "note.delete"
+	AuditNoteAttach       = "note.attachment.add"
+	AuditNoteDetach       = "note.attachment.delete"
+)
+
+// AuditEntry: satu baris audit_log. Before/After berisi snapshot JSON data
+// sebelum dan sesudah perubahan (null untuk insert/hapus).
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	NIK       string          `json:"nik"`
+	Date      string          `json:"date"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Reason    string          `json:"reason"`
+	ClientIP  string          `json:"client_ip"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditContext: siapa, dari mana dan kenapa sebuah perubahan manual dilakukan
+type AuditContext struct {
+	Actor    string
+	Reason   string
+	ClientIP string
+}
+
+// AuditScan: snapshot satu scan di audit_log
+type AuditScan struct {
+	ID        int64     `json:"id,omitempty"`
+	NIK       string    `json:"nik"`
+	Timestamp time.Time `json:"timestamp"`
+	Direction string    `json:"direction,omitempty"`
+}
+
+// AuditNote: snapshot catatan harian di audit_log
+type AuditNote struct {
+	Category string `json:"category,omitempty"`
+	Note     string `json:"note"`
+}
+
+// AuditFilter: parameter GET /audit
+type AuditFilter struct {
+	NIK    string `query:"nik"`
+	From   string `query:"from"` // Tanggal data, format "YYYY-MM-DD"
+	To     string `query:"to"`
+	Actor  string `query:"actor"`
+	Action string `query:"action"`
+	Limit  int    `query:"limit"`
+}
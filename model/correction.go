@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Jenis koreksi absensi
+const (
+	CorrectionAdd    = "add"    // Tambah scan yang terlupa
+	CorrectionDelete = "delete" // Hapus scan yang salah
+)
+
+// CorrectionRouteHR: assigned_to untuk karyawan tanpa atasan langsung
+const CorrectionRouteHR = "HR"
+
+type CorrectionRequest struct {
+	ID           int                 `json:"id"`
+	NIK          string              `json:"nik"`
+	Action       string              `json:"action"`
+	Timestamp    *time.Time          `json:"timestamp,omitempty"`
+	Direction    string              `json:"direction,omitempty"`
+	LogID        *int64              `json:"log_id,omitempty"`
+	Reason       string              `json:"reason"`
+	Status       string              `json:"status"`
+	RequestedBy  string              `json:"requested_by"`
+	AssignedTo   string              `json:"assigned_to"`
+	DecidedBy    string              `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time          `json:"decided_at,omitempty"`
+	DecisionNote string              `json:"decision_note,omitempty"`
+	AppliedLogID *int64              `json:"applied_log_id,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Comments     []CorrectionComment `json:"comments,omitempty"`
+}
+
+type CreateCorrectionRequest struct {
+	NIK         string `json:"nik"`
+	Action      string `json:"action"`    // "add" atau "delete"
+	Timestamp   string `json:"timestamp"` // action "add", format "YYYY-MM-DD HH:mm:ss" zona site
+	Direction   string `json:"direction"` // action "add", opsional "IN"/"OUT"
+	LogID       int64  `json:"log_id"`    // action "delete"
+	Reason      string `json:"reason"`
+	RequestedBy string `json:"requested_by"`
+}
+
+type CorrectionComment struct {
+	ID        int       `json:"id"`
+	RequestID int       `json:"request_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AddCommentRequest struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+type CorrectionRequestFilter struct {
+	NIK        string `query:"nik"`
+	Status     string `query:"status"`
+	AssignedTo string `query:"assigned_to"`
+}
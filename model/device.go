@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// ScanDevice: scanner sidik jari. DebounceSeconds nil = pakai pengaturan global.
+type ScanDevice struct {
+	Code            string    `json:"code"`
+	Name            string    `json:"name"`
+	DebounceSeconds *int      `json:"debounce_seconds"`
+	Direction       string    `json:"direction"` // "IN", "OUT" atau kosong (dua arah)
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type SaveDeviceRequest struct {
+	Code            string `json:"code"`
+	Name            string `json:"name"`
+	DebounceSeconds *int   `json:"debounce_seconds"`
+	Direction       string `json:"direction"`
+}
+
+// Arah scan
+const (
+	DirectionIn  = "IN"
+	DirectionOut = "OUT"
+)
+
+// Asal penentuan arah scan
+const (
+	DirectionSourceSensor   = "sensor"   // Dikirim NodeMCU (tombol fungsi)
+	DirectionSourceDevice   = "device"   // Default perangkat (scanner khusus masuk/keluar)
+	DirectionSourceInferred = "inferred" // Ditebak server dari urutan scan dan shift
+	DirectionSourceManual   = "manual"
+)
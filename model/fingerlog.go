@@ -3,36 +3,150 @@ package model
 import "time"
 
 type FingerLogResult struct {
-	NIK        string      `json:"nik"`
-	FullName   string      `json:"full_name"`
-	Timestamps []time.Time `json:"timestamps"` // Slice yang akan diisi
+	NIK        string           `json:"nik"`
+	FullName   string           `json:"full_name"`
+	Date       string           `json:"date,omitempty"`
+	Timestamps []time.Time      `json:"timestamps"` // Slice yang akan diisi
+	Entries    []FingerLogEntry `json:"entries"`    // Sama dengan Timestamps, ditambah arah scan
+}
+
+type FingerLogEntry struct {
+	ID        int64     `json:"id"` // Dipakai untuk DELETE/PATCH /attendance/:id
+	Timestamp time.Time `json:"timestamp"`
+	Direction string    `json:"direction"` // "IN", "OUT" atau kosong untuk data lama
 }
 
 // Structure sementara untuk memindai setiap baris dari database
 type RawFingerLog struct {
+	ID        int64
 	NIK       string
 	FullName  string
+	Date      string // Tanggal kelompok, format "YYYY-MM-DD"
 	Timestamp time.Time
+	Direction string
 }
 
 type FingerLogRequest struct {
 	Date string `json:"date"`
+	OrgFilter
+}
+
+// Kategori catatan harian
+const (
+    NoteSick        = "sick"         // Sakit
+    NotePermit      = "permit"       // Izin
+    NoteDutyOutside = "duty_outside" // Dinas luar
+    NoteLateReason  = "late_reason"  // Alasan terlambat
+    NoteOther       = "other"
+)
+
+// IsNoteCategory: kategori dikenal (lihat konstanta Note*)
+func IsNoteCategory(category string) bool {
+    switch category {
+    case NoteSick, NotePermit, NoteDutyOutside, NoteLateReason, NoteOther:
+        return true
+    }
+    return false
 }
 
 type NoteRequest struct {
-    Date string `json:"date"`
-    NIK  string `json:"nik"`
-    Note string `json:"note"`
+    Date     string `json:"date"`
+    NIK      string `json:"nik"`
+    Category string `json:"category"` // Kosong = "other"
+    Note     string `json:"note"`
+    Reason   string `json:"reason"` // Wajib, alasan perubahan
 }
 
 type NoteResponse struct {
-    NIK  string `json:"nik"`
-    Note string `json:"note"`
+    NIK       string     `json:"nik"`
+    Date      string     `json:"date,omitempty"`
+    Category  string     `json:"category"`
+    Note      string     `json:"note"`
+    UpdatedBy string     `json:"updated_by,omitempty"`
+    UpdatedAt *time.Time `json:"updated_at,omitempty"`
+    // Jumlah lampiran, daftar lengkap di GET /notes/attachments
+    AttachmentCount int `json:"attachment_count"`
+}
+
+// NoteRevision: satu versi catatan, untuk GET /notes/history
+type NoteRevision struct {
+    ID        int64     `json:"id"`
+    NIK       string    `json:"nik"`
+    Date      string    `json:"date"`
+    Category  string    `json:"category"`
+    Note      string    `json:"note"`
+    Author    string    `json:"author"`
+    Reason    string    `json:"reason"`
+    Deleted   bool      `json:"deleted"` // Revisi penghapusan, isi = catatan terakhir
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// NoteQuery: parameter GET /notes. date = satu tanggal, atau from/to untuk rentang.
+type NoteQuery struct {
+	Date string `query:"date"`
+	From string `query:"from"`
+	To   string `query:"to"`
+	NIK  string `query:"nik"`
+}
+
+// DeleteNoteRequest: body/query DELETE /notes
+type DeleteNoteRequest struct {
+	NIK    string `json:"nik" query:"nik"`
+	Date   string `json:"date" query:"date"`
+	Reason string `json:"reason" query:"reason"`
+}
+
+// BulkNoteRequest: body POST /notes/bulk. Satu catatan diterapkan ke setiap
+// karyawan (niks dan/atau filter organisasi) pada setiap tanggal di [from, to].
+type BulkNoteRequest struct {
+	NIKs            []string `json:"niks"`
+	From            string   `json:"from"` // Format: "YYYY-MM-DD"
+	To              string   `json:"to"`   // Kosong = sama dengan from
+	Category        string   `json:"category"`
+	Note            string   `json:"note"`
+	Overwrite       bool     `json:"overwrite"`         // false = catatan yang sudah ada dibiarkan
+	IncludeRestDays bool     `json:"include_rest_days"` // false = hari tanpa shift terjadwal dilewati
+	Reason          string   `json:"reason"`
+	OrgFilter
+}
+
+// NoteTarget: satu NIK pada satu tanggal ("YYYY-MM-DD")
+type NoteTarget struct {
+	NIK  string
+	Date string
+}
+
+// NoteAttachment: metadata lampiran catatan; isi file ada di blob store
+type NoteAttachment struct {
+	ID          int       `json:"id"`
+	NIK         string    `json:"nik"`
+	Date        string    `json:"date"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DeleteAttachmentRequest: body/query DELETE /notes/attachments/:id
+type DeleteAttachmentRequest struct {
+	Reason string `json:"reason" query:"reason"`
+}
+
+type BulkNoteResult struct {
+	Employees int `json:"employees"`
+	Applied   int `json:"applied"`
+	Skipped   int `json:"skipped"`   // Sudah punya catatan dan overwrite = false
+	RestDays  int `json:"rest_days"` // Hari off/libur yang dilewati
 }
 
 type AddManualFingerLogRequest struct {
     NIK       string `json:"nik" form:"nik"`
     Timestamp string `json:"timestamp" form:"timestamp"` // Format: "YYYY-MM-DD HH:mm:ss"
+    Direction string `json:"direction" form:"direction"` // Opsional: "IN" / "OUT"
+    Reason    string `json:"reason" form:"reason"`       // Wajib, alasan koreksi
 }
 
 type SuccessResponse struct {
@@ -43,4 +157,64 @@ type SuccessResponse struct {
 type DeleteFingerLogRequest struct {
     NIK       string `json:"nik"`
     Timestamp string `json:"timestamp"` // Format: "YYYY-MM-DD HH:mm:ss"
-}
\ No newline at end of file
+    Reason    string `json:"reason"`    // Wajib, alasan penghapusan
+}
+
+// DeleteAttendanceRequest: body/query DELETE /attendance/:id
+type DeleteAttendanceRequest struct {
+	Reason string `json:"reason" query:"reason"`
+}
+
+// RestoreAttendanceRequest: body POST /attendance/:id/restore (khusus admin).
+// Actor diambil dari token Authorization, bukan dari body.
+type RestoreAttendanceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// DeletedFingerLog: log yang dihapus lunak, untuk GET /attendance/deleted
+type DeletedFingerLog struct {
+	ID              int64      `json:"id"`
+	NIK             string     `json:"nik"`
+	FullName        string     `json:"full_name"`
+	Timestamp       time.Time  `json:"timestamp"`
+	Direction       string     `json:"direction"`
+	DeletedAt       time.Time  `json:"deleted_at"`
+	DeletedBy       string     `json:"deleted_by"`
+	DeleteReason    string     `json:"delete_reason"`
+	ReplacedBy      *int64     `json:"replaced_by"`      // Diisi jika terhapus karena dikoreksi
+	RestorableUntil *time.Time `json:"restorable_until"` // nil = tidak bisa dipulihkan (dikoreksi atau retensi lewat)
+}
+
+// DeletedFingerLogFilter: parameter GET /attendance/deleted. Tanpa from/to,
+// dikembalikan log yang dihapus selama masa retensi.
+type DeletedFingerLogFilter struct {
+	From string `query:"from"` // Tanggal scan, format "YYYY-MM-DD"
+	To   string `query:"to"`
+	NIK  string `query:"nik"`
+}
+
+// CorrectAttendanceRequest: body PATCH /attendance/:id, koreksi jam scan.
+// Log lama dihapus lunak dan diganti log baru dengan jam yang benar.
+type CorrectAttendanceRequest struct {
+	Timestamp string `json:"timestamp"` // Format: "YYYY-MM-DD HH:mm:ss", zona site
+	Direction string `json:"direction"` // Opsional, kosong = arah log lama
+	Reason    string `json:"reason"`
+}
+
+// AttendanceQuery: parameter GET /attendance dan GET /users/:nik/attendance
+type AttendanceQuery struct {
+	From       string `query:"from"` // Format: "YYYY-MM-DD"
+	To         string `query:"to"`
+	NIK        string `query:"nik"`
+	Department int    `query:"department"` // Alias department_id
+	Page       int    `query:"page"`
+	Limit      int    `query:"limit"`
+	OrgFilter
+}
+
+type PagedFingerLogResult struct {
+	Data  []FingerLogResult `json:"data"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+	Total int               `json:"total"`
+}
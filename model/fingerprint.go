@@ -6,10 +6,12 @@ type ScanCommand struct {
 }
 
 type SensorResponse struct {
-	Action  string `json:"action"`
-	ID      int    `json:"id"`
-	Status  string `json:"status"`
-	Trigger string `json:"trigger"`
+	Action    string `json:"action"`
+	ID        int    `json:"id"`
+	Status    string `json:"status"`
+	Trigger   string `json:"trigger"`
+	Device    string `json:"device"`    // Kode scanner (opsional)
+	Direction string `json:"direction"` // "IN"/"OUT" dari tombol fungsi (opsional)
 }
 
 type AddFingerRequest struct {
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Jenis hari libur
+const (
+	HolidayPublic  = "public"  // Libur nasional / cuti bersama
+	HolidayCompany = "company" // Libur perusahaan, misal shutdown pabrik
+)
+
+type Holiday struct {
+	ID           int       `json:"id"`
+	Date         time.Time `json:"date"`
+	Name         string    `json:"name"`
+	Kind         string    `json:"kind"`
+	DepartmentID *int      `json:"department_id,omitempty"`
+	Site         string    `json:"site,omitempty"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AppliesTo: hari libur tanpa cakupan berlaku untuk semua karyawan
+func (h Holiday) AppliesTo(departmentID int, site string) bool {
+	if h.DepartmentID != nil && *h.DepartmentID != departmentID {
+		return false
+	}
+	return h.Site == "" || h.Site == site
+}
+
+type CreateHolidayRequest struct {
+	Date         string `json:"date"`     // Format: "YYYY-MM-DD"
+	EndDate      string `json:"end_date"` // Opsional, untuk libur beberapa hari
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	DepartmentID *int   `json:"department_id"`
+	Site         string `json:"site"`
+}
+
+type HolidayFilter struct {
+	Year         int    `query:"year"`
+	DepartmentID int    `query:"department_id"`
+	Site         string `query:"site"`
+}
+
+type HolidayImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"` // Tanggal yang sudah ada pada cakupan yang sama
+}
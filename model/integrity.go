@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Nama rantai hash, sama dengan nama tabelnya
+const (
+	ChainFingerLog = "fingerlog"
+	ChainAudit     = "audit_log"
+)
+
+var Chains = []string{ChainFingerLog, ChainAudit}
+
+// ChainRecord: satu baris rantai. Fields berisi nilai kolom yang di-hash,
+// sudah dalam bentuk teks kanonik (lihat migrations/012_hash_chain.sql).
+type ChainRecord struct {
+	Seq      int64
+	PrevHash string
+	Hash     string
+	Fields   []string
+}
+
+type ChainHead struct {
+	Chain string `json:"chain"`
+	Seq   int64  `json:"seq"`
+	Hash  string `json:"hash"`
+}
+
+// ChainBreak: tautan pertama yang rusak pada satu rantai
+type ChainBreak struct {
+	Seq      int64  `json:"seq"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+type ChainReport struct {
+	Chain     string      `json:"chain"`
+	Verified  int64       `json:"verified"`  // Jumlah baris yang diperiksa
+	Unchained int64       `json:"unchained"` // Baris lama sebelum rantai diaktifkan
+	Head      ChainHead   `json:"head"`
+	Broken    *ChainBreak `json:"broken"` // nil = utuh
+}
+
+type VerifyReport struct {
+	Valid              bool          `json:"valid"`
+	CheckedAt          time.Time     `json:"checked_at"`
+	Chains             []ChainReport `json:"chains"`
+	Checkpoints        int           `json:"checkpoints"`         // Jumlah checkpoint yang dicocokkan
+	InvalidCheckpoints []string      `json:"invalid_checkpoints"` // Tanggal checkpoint dengan tanda tangan tidak sah
+}
+
+// CheckpointPayload: isi yang ditandatangani untuk satu hari
+type CheckpointPayload struct {
+	Date      string      `json:"date"`
+	Timezone  string      `json:"timezone"`
+	CreatedAt time.Time   `json:"created_at"`
+	Heads     []ChainHead `json:"heads"`
+}
+
+// SignedCheckpoint: bentuk ekspor checkpoint. Signature (ed25519, base64)
+// dihitung atas byte Payload persis seperti yang dikirim.
+type SignedCheckpoint struct {
+	Date      string          `json:"date"`
+	Algorithm string          `json:"algorithm"`
+	Payload   json.RawMessage `json:"payload"`
+	Signature string          `json:"signature"`
+	PublicKey string          `json:"public_key"`
+}
@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// Jenis cuti/izin
+const (
+	LeaveAnnual    = "annual"
+	LeaveSick      = "sick"
+	LeavePermit    = "permit"
+	LeaveMaternity = "maternity"
+	LeaveUnpaid    = "unpaid"
+)
+
+// DefaultAnnualLeaveDays: hak cuti tahunan minimal (UU 13/2003 pasal 79)
+const DefaultAnnualLeaveDays = 12
+
+type LeaveType struct {
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	MatrixCode     string `json:"matrix_code"`     // Kode pada lembar absensi
+	DeductsBalance bool   `json:"deducts_balance"` // Memotong hak cuti tahunan
+	Paid           bool   `json:"paid"`
+}
+
+var LeaveTypes = []LeaveType{
+	{Code: LeaveAnnual, Name: "Cuti Tahunan", MatrixCode: CodeCuti, DeductsBalance: true, Paid: true},
+	{Code: LeaveSick, Name: "Sakit", MatrixCode: CodeSakit, Paid: true},
+	{Code: LeavePermit, Name: "Izin", MatrixCode: CodeIzin, Paid: true},
+	{Code: LeaveMaternity, Name: "Cuti Melahirkan", MatrixCode: CodeCuti, Paid: true},
+	{Code: LeaveUnpaid, Name: "Cuti Tidak Dibayar", MatrixCode: CodeIzin},
+}
+
+// FindLeaveType: nil jika kode tidak dikenal
+func FindLeaveType(code string) *LeaveType {
+	for i := range LeaveTypes {
+		if LeaveTypes[i].Code == code {
+			return &LeaveTypes[i]
+		}
+	}
+	return nil
+}
+
+type LeaveRequest struct {
+	ID           int        `json:"id"`
+	NIK          string     `json:"nik"`
+	LeaveType    string     `json:"leave_type"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      time.Time  `json:"end_date"`
+	Days         int        `json:"days"`
+	Reason       string     `json:"reason"`
+	Status       string     `json:"status"`
+	RequestedBy  string     `json:"requested_by"`
+	DecidedBy    string     `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type CreateLeaveRequest struct {
+	NIK         string `json:"nik"`
+	LeaveType   string `json:"leave_type"`
+	StartDate   string `json:"start_date"` // Format: "YYYY-MM-DD"
+	EndDate     string `json:"end_date"`
+	Reason      string `json:"reason"`
+	RequestedBy string `json:"requested_by"`
+}
+
+type LeaveRequestFilter struct {
+	NIK    string `query:"nik"`
+	Status string `query:"status"`
+	From   string `query:"from"`
+	To     string `query:"to"`
+}
+
+// LeaveBalance: hak cuti tahunan. Remaining = Entitled - Used; Pending belum dipotong.
+type LeaveBalance struct {
+	NIK       string `json:"nik"`
+	Year      int    `json:"year"`
+	Entitled  int    `json:"entitled_days"`
+	Used      int    `json:"used_days"`
+	Pending   int    `json:"pending_days"`
+	Remaining int    `json:"remaining_days"`
+}
+
+type SetLeaveBalanceRequest struct {
+	Year         int `json:"year"`
+	EntitledDays int `json:"entitled_days"`
+}
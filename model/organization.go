@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+type Department struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Site      string    `json:"site,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateDepartmentRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Site string `json:"site"`
+}
+
+type ProductionLine struct {
+	ID           int       `json:"id"`
+	DepartmentID int       `json:"department_id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CreateLineRequest struct {
+	DepartmentID int    `json:"department_id"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+}
+
+// UserAssignment: periode penugasan user ke departemen/line beserta atasannya
+type UserAssignment struct {
+	ID            int        `json:"id"`
+	NIK           string     `json:"nik"`
+	DepartmentID  int        `json:"department_id"`
+	LineID        *int       `json:"line_id"`
+	SupervisorNIK string     `json:"supervisor_nik"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+type AssignUserRequest struct {
+	DepartmentID  int    `json:"department_id"`
+	LineID        *int   `json:"line_id"`
+	SupervisorNIK string `json:"supervisor_nik"`
+	EffectiveFrom string `json:"effective_from"` // Format: "YYYY-MM-DD"
+	EffectiveTo   string `json:"effective_to"`   // Opsional, kosong = masih berlaku
+}
+
+// OrgFilter: filter berdasarkan struktur organisasi yang berlaku pada tanggal data
+type OrgFilter struct {
+	DepartmentID  int    `json:"department_id" query:"department_id"`
+	LineID        int    `json:"line_id" query:"line_id"`
+	SupervisorNIK string `json:"supervisor_nik" query:"supervisor_nik"`
+}
+
+func (f OrgFilter) IsEmpty() bool {
+	return f.DepartmentID == 0 && f.LineID == 0 && f.SupervisorNIK == ""
+}
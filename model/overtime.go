@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// Status pengajuan (dipakai juga oleh pengajuan lain yang butuh persetujuan)
+const (
+	RequestSubmitted = "submitted"
+	RequestApproved  = "approved"
+	RequestRejected  = "rejected"
+)
+
+type OvertimeRequest struct {
+	ID             int        `json:"id"`
+	NIK            string     `json:"nik"`
+	Date           time.Time  `json:"date"`
+	PlannedMinutes int        `json:"planned_minutes"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	RequestedBy    string     `json:"requested_by"`
+	DecidedBy      string     `json:"decided_by,omitempty"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+	DecisionNote   string     `json:"decision_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type CreateOvertimeRequest struct {
+	NIK            string `json:"nik"`
+	Date           string `json:"date"` // Format: "YYYY-MM-DD"
+	PlannedMinutes int    `json:"planned_minutes"`
+	Reason         string `json:"reason"`
+	RequestedBy    string `json:"requested_by"`
+}
+
+type DecideRequest struct {
+	DecidedBy string `json:"decided_by"`
+	Note      string `json:"note"`
+}
+
+type OvertimeRequestFilter struct {
+	Date   string `query:"date"`
+	NIK    string `query:"nik"`
+	Status string `query:"status"`
+}
+
+// OvertimeTiers: menit lembur per pengali upah (Kepmenakertrans 102/2004, PP 35/2021)
+type OvertimeTiers struct {
+	X1_5 int `json:"x1_5"`
+	X2   int `json:"x2"`
+	X3   int `json:"x3"`
+	X4   int `json:"x4"`
+}
+
+// OvertimeDay: lembur satu karyawan pada satu hari. Menit dibayar = aktual dibatasi persetujuan.
+type OvertimeDay struct {
+	NIK             string        `json:"nik"`
+	FullName        string        `json:"full_name"`
+	DepartmentName  string        `json:"department_name"`
+	Date            string        `json:"date"`
+	IsRestDay       bool          `json:"is_rest_day"`
+	ActualMinutes   int           `json:"actual_minutes"`
+	ApprovedMinutes int           `json:"approved_minutes"`
+	PaidMinutes     int           `json:"paid_minutes"`
+	Tiers           OvertimeTiers `json:"tiers"`
+	WeightedHours   float64       `json:"weighted_hours"` // Jam lembur x pengali, dasar perhitungan upah
+}
+
+type OvertimeMonthly struct {
+	NIK             string        `json:"nik"`
+	FullName        string        `json:"full_name"`
+	DepartmentName  string        `json:"department_name"`
+	Month           string        `json:"month"`
+	Days            int           `json:"days"`
+	ActualMinutes   int           `json:"actual_minutes"`
+	ApprovedMinutes int           `json:"approved_minutes"`
+	PaidMinutes     int           `json:"paid_minutes"`
+	Tiers           OvertimeTiers `json:"tiers"`
+	WeightedHours   float64       `json:"weighted_hours"`
+}
+
+// OvertimeReportRequest: GET /overtime/daily?date=... atau /overtime/monthly?month=...
+type OvertimeReportRequest struct {
+	Date  string `query:"date"`
+	Month string `query:"month"`
+	OrgFilter
+}
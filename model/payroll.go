@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// Kebijakan template untuk karyawan tanpa ID payroll (Identifier = "mapped")
+const (
+	UnmappedError = "error" // Export ditolak, daftar NIK dikembalikan
+	UnmappedSkip  = "skip"  // Karyawan dilewati
+	UnmappedNIK   = "nik"   // NIK dipakai sebagai ID
+)
+
+// Sumber kolom employee_id
+const (
+	IdentifierNIK    = "nik"
+	IdentifierMapped = "mapped" // Dari payroll_employee_ids template
+)
+
+// PayrollTotals: total absensi satu karyawan dalam satu periode payroll
+type PayrollTotals struct {
+	NIK                   string         `json:"nik"`
+	FullName              string         `json:"full_name"`
+	DepartmentName        string         `json:"department_name"`
+	From                  string         `json:"from"`
+	To                    string         `json:"to"`
+	Workdays              int            `json:"workdays"` // Hari terjadwal (shift, bukan libur)
+	DaysPresent           int            `json:"days_present"`
+	LateCount             int            `json:"late_count"`
+	LateMinutes           int            `json:"late_minutes"`
+	EarlyLeaveMinutes     int            `json:"early_leave_minutes"`
+	IncompleteDays        int            `json:"incomplete_days"`
+	Absences              int            `json:"absences"`
+	LeaveDays             int            `json:"leave_days"`
+	LeaveByType           map[string]int `json:"leave_by_type"` // Kode jenis cuti, atau kategori catatan untuk izin tanpa pengajuan
+	WorkedHours           float64        `json:"worked_hours"`
+	OvertimeHours         float64        `json:"overtime_hours"` // Lembur dibayar
+	OvertimeTiers         OvertimeTiers  `json:"overtime_tiers"` // Menit per pengali
+	OvertimeWeightedHours float64        `json:"overtime_weighted_hours"`
+}
+
+// PayrollColumn: satu kolom file payroll. Field salah satu nama di katalog
+// (lihat package payroll), "leave:<kode>" atau "const" dengan isi Value.
+type PayrollColumn struct {
+	Header   string `json:"header"`
+	Field    string `json:"field"`
+	Value    string `json:"value,omitempty"`    // Isi tetap untuk field "const"
+	Decimals *int   `json:"decimals,omitempty"` // Override Decimals template untuk kolom ini
+}
+
+// PayrollTemplate: tata letak file import sistem payroll
+type PayrollTemplate struct {
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	Delimiter        string          `json:"delimiter"`         // Satu karakter, default ","; "\t" untuk TSV
+	SkipHeader       bool            `json:"skip_header"`       // true = baris header tidak ditulis
+	DecimalSeparator string          `json:"decimal_separator"` // "." (default) atau ","
+	Decimals         *int            `json:"decimals"`          // Digit desimal angka jam, default 2
+	DateFormat       string          `json:"date_format"`       // Token YYYY, YY, MM, DD; default "YYYY-MM-DD"
+	Identifier       string          `json:"identifier"`        // "nik" (default) atau "mapped"
+	Unmapped         string          `json:"unmapped"`          // "error" (default), "skip" atau "nik"
+	Columns          []PayrollColumn `json:"columns"`
+	UpdatedAt        *time.Time      `json:"updated_at,omitempty"`
+}
+
+// PayrollEmployeeID: pemetaan NIK ke ID karyawan di sistem payroll.
+// ExternalID kosong menghapus pemetaan.
+type PayrollEmployeeID struct {
+	NIK        string `json:"nik"`
+	ExternalID string `json:"external_id"`
+}
+
+// PayrollPeriodRequest: month=YYYY-MM, atau from/to untuk periode cut-off
+type PayrollPeriodRequest struct {
+	Month    string `query:"month"`
+	From     string `query:"from"`
+	To       string `query:"to"`
+	Template string `query:"template"`
+	OrgFilter
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Jendela scan default (menit sebelum mulai / setelah selesai shift)
+const (
+	DefaultWindowBeforeMinutes = 180
+	DefaultWindowAfterMinutes  = 360
+)
+
+// Shift: template jam kerja. StartTime/EndTime dalam format "HH:MM".
+type Shift struct {
+	ID                  int    `json:"id"`
+	Code                string `json:"code"`
+	Name                string `json:"name"`
+	StartTime           string `json:"start_time"`
+	EndTime             string `json:"end_time"`
+	GraceMinutes        int    `json:"grace_minutes"`
+	BreakMinutes        int    `json:"break_minutes"`
+	WindowBeforeMinutes int    `json:"window_before_minutes"`
+	WindowAfterMinutes  int    `json:"window_after_minutes"`
+}
+
+type CreateShiftRequest struct {
+	Code                string `json:"code"`
+	Name                string `json:"name"`
+	StartTime           string `json:"start_time"` // Format: "HH:MM"
+	EndTime             string `json:"end_time"`   // Format: "HH:MM"
+	GraceMinutes        int    `json:"grace_minutes"`
+	BreakMinutes        int    `json:"break_minutes"`
+	WindowBeforeMinutes *int   `json:"window_before_minutes"` // Opsional, default 180
+	WindowAfterMinutes  *int   `json:"window_after_minutes"`  // Opsional, default 360
+}
+
+// ParseClock: mengubah "HH:MM" menjadi menit sejak tengah malam
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("format jam '%s' salah, gunakan HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// CrossesMidnight: true jika jam selesai jatuh di hari berikutnya (misal 23:00-07:00)
+func (s Shift) CrossesMidnight() bool {
+	start, _ := ParseClock(s.StartTime)
+	end, _ := ParseClock(s.EndTime)
+	return end <= start
+}
+
+// Window: waktu mulai dan selesai shift untuk tanggal kerja `date`,
+// mengikuti lokasi (zona waktu) dari `date`.
+func (s Shift) Window(date time.Time) (time.Time, time.Time) {
+	start, _ := ParseClock(s.StartTime)
+	end, _ := ParseClock(s.EndTime)
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	startAt := day.Add(time.Duration(start) * time.Minute)
+	endAt := day.Add(time.Duration(end) * time.Minute)
+	if end <= start {
+		endAt = endAt.AddDate(0, 0, 1)
+	}
+	return startAt, endAt
+}
+
+// ScanWindow: rentang waktu scan yang dihitung untuk shift pada tanggal kerja `date`
+func (s Shift) ScanWindow(date time.Time) (time.Time, time.Time) {
+	start, end := s.Window(date)
+	return start.Add(-time.Duration(s.WindowBeforeMinutes) * time.Minute),
+		end.Add(time.Duration(s.WindowAfterMinutes) * time.Minute)
+}
+
+type PatternDay struct {
+	DayIndex  int    `json:"day_index"`
+	ShiftID   *int   `json:"shift_id"`
+	ShiftCode string `json:"shift_code"` // Kosong = libur
+}
+
+// SchedulePattern: pola bergilir, contoh 3 shift: P,P,S,S,M,M,OFF,OFF
+type SchedulePattern struct {
+	ID   int          `json:"id"`
+	Code string       `json:"code"`
+	Name string       `json:"name"`
+	Days []PatternDay `json:"days"`
+}
+
+type CreatePatternRequest struct {
+	Code string   `json:"code"`
+	Name string   `json:"name"`
+	Days []string `json:"days"` // Kode shift per hari, "OFF" atau "" untuk libur
+}
+
+type ScheduleAssignment struct {
+	ID        int        `json:"id"`
+	PatternID int        `json:"pattern_id"`
+	NIK       string     `json:"nik,omitempty"`
+	LineID    *int       `json:"line_id,omitempty"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+type AssignScheduleRequest struct {
+	PatternID int    `json:"pattern_id"`
+	NIK       string `json:"nik"`
+	LineID    *int   `json:"line_id"`
+	StartDate string `json:"start_date"` // Hari ke-0 siklus, format "YYYY-MM-DD"
+	EndDate   string `json:"end_date"`   // Opsional
+}
+
+// PlannedShift: shift yang direncanakan untuk satu user di satu tanggal
+type PlannedShift struct {
+	NIK         string `json:"nik"`
+	Date        string `json:"date"`
+	PatternCode string `json:"pattern_code,omitempty"`
+	Source      string `json:"source,omitempty"` // "user" atau "line"
+	IsOff       bool   `json:"is_off"`
+	Holiday     string `json:"holiday,omitempty"` // Nama hari libur jika tanggal tsb libur
+	Shift       *Shift `json:"shift"`
+}
+
+// ScheduleData: potongan data jadwal yang dibutuhkan untuk menghitung shift
+// banyak user sekaligus tanpa query per user per hari.
+type ScheduleData struct {
+	Shifts          map[int]Shift
+	Patterns        map[int]SchedulePattern
+	Assignments     []ScheduleAssignment
+	UserAssignments []UserAssignment
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"sync" // PENTING: Import ini untuk Mutex
 
 	"Steril-App/internal/repository" // Sesuaikan import path
-	"Steril-App/model"               // Sesuaikan import path
+	"Steril-App/internal/service"
+	"Steril-App/model" // Sesuaikan import path
 
 	// "Steril-App/ws"
 
@@ -20,16 +23,27 @@ type WebSocketHandler struct {
 	RepoFingerSocket *repository.AddFingerRepository
 	RepoFinger       *repository.FingerRepository
 	RepoLogFinger    *repository.FingerLogRepository
+	ScanService      *service.ScanService
 }
 
-func NewWebSocketHandler(repoFingerSocket *repository.AddFingerRepository, repoFinger *repository.FingerRepository, repoLogFinger *repository.FingerLogRepository) *WebSocketHandler {
+func NewWebSocketHandler(repoFingerSocket *repository.AddFingerRepository, repoFinger *repository.FingerRepository, repoLogFinger *repository.FingerLogRepository, scanService *service.ScanService) *WebSocketHandler {
 	return &WebSocketHandler{
 		RepoFingerSocket: repoFingerSocket,
 		RepoFinger:       repoFinger,
 		RepoLogFinger:    repoLogFinger,
+		ScanService:      scanService,
 	}
 }
 
+// DebounceSecondsFromEnv: SCAN_DEBOUNCE_SECONDS, default 60 detik
+func DebounceSecondsFromEnv() int {
+	secs, err := strconv.Atoi(os.Getenv("SCAN_DEBOUNCE_SECONDS"))
+	if err != nil || secs < 0 {
+		return 60
+	}
+	return secs
+}
+
 // --- BAGIAN INI DIPERBAIKI ---
 
 // Variabel global dengan pengamanan Mutex
@@ -104,12 +118,16 @@ func (h *WebSocketHandler) HandleWebSocket(c echo.Context) error {
 				continue
 			}
 
-			err = h.RepoLogFinger.AddFingerLog(nik)
+			isDuplicate, err := h.ScanService.RecordScan(nik, response.Device, response.Direction)
 			if err != nil {
 				log.Println("❌ gagal tambah log absensi:", err)
 				continue
 			}
 
+			if isDuplicate {
+				fmt.Println("🔁 Scan ganda (debounce) untuk ID:", response.ID)
+				continue
+			}
 			fmt.Println("✅ Data Absensi Diterima untuk ID:", response.ID)
 
 		} else {